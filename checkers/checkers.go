@@ -0,0 +1,383 @@
+package checkers
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// BoardSize is the side length of an English draughts board.
+const BoardSize = 8
+
+// Kind distinguishes uncrowned men from kings. It is stored in the Kind of
+// each engine.Piece in Game.Pieces.
+type Kind int
+
+const (
+	Empty Kind = 0
+	Man   Kind = 1
+	King  Kind = 2
+)
+
+// Rules implements English draughts: men move diagonally forward, captures are
+// mandatory and must be continued while possible, and men reaching the far row
+// are crowned, which ends the move.
+type Rules struct {
+	// DrawMoves is the number of moves per player without a capture or a man
+	// moving after which the game is drawn. Zero disables the rule.
+	DrawMoves int
+}
+
+// NewRules returns English draughts rules with the 40-move draw rule.
+func NewRules() Rules {
+	return Rules{DrawMoves: 40}
+}
+
+func init() {
+	r := NewRules()
+	engine.Register(engine.GameDefinition{
+		Name:       "checkers",
+		Title:      "Checkers",
+		MinPlayers: 2,
+		MaxPlayers: 2,
+		Rows:       BoardSize,
+		Cols:       BoardSize,
+		Players:    []engine.Player{{ID: 1, Name: "Black", Token: "b"}, {ID: 2, Name: "White", Token: "w"}},
+		NewGame:    r.NewGame,
+		Rule:       r,
+		Render:     RenderBoard,
+		Notation:   Notation{Rules: r},
+		MoveHelp:   "squares 1-32, e.g. 11-15 or 22x15",
+	})
+}
+
+// NewGame sets up the standard opening position. The first player moves first
+// and starts on squares 1-12 at the top of the board; the second starts on 21-32.
+func (r Rules) NewGame(players []engine.Player) (*engine.Game, error) {
+	if len(players) != 2 {
+		return nil, fmt.Errorf("checkers requires 2 players, got %d", len(players))
+	}
+	board := engine.NewBoard(BoardSize, BoardSize)
+	cells := engine.NewGrid[engine.Piece](BoardSize, BoardSize)
+	for row := 0; row < BoardSize; row++ {
+		var owner int
+		switch {
+		case row < 3:
+			owner = players[0].ID
+		case row >= BoardSize-3:
+			owner = players[1].ID
+		default:
+			continue
+		}
+		for col := 0; col < BoardSize; col++ {
+			pos := engine.Position{Row: row, Col: col}
+			if isDark(pos) {
+				if err := board.Set(pos, owner); err != nil {
+					return nil, err
+				}
+				if err := cells.Set(pos, engine.Piece{Owner: owner, Kind: int(Man)}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	g, err := engine.NewGame(board, players)
+	if err != nil {
+		return nil, err
+	}
+	g.Pieces = cells
+	return g, nil
+}
+
+// ValidMoves returns the legal moves for the current player. When any capture
+// is available only complete capture sequences are returned.
+func (r Rules) ValidMoves(g *engine.Game) []engine.Move {
+	if g.Outcome.Winner != nil || g.Outcome.Draw {
+		return nil
+	}
+	return movesFor(g, g.CurrentPlayer().ID)
+}
+
+// ApplyMove moves a piece along one of the valid paths, removes jumped pieces,
+// and crowns men that reach the far row. Moves are refused once Status reports
+// the game over.
+func (r Rules) ApplyMove(g *engine.Game, m engine.Move) error {
+	if _, over := r.Status(g); over {
+		return fmt.Errorf("game is finished")
+	}
+	mover := g.CurrentPlayer().ID
+	if m.PlayerID != mover {
+		return fmt.Errorf("it is not player %d's turn", m.PlayerID)
+	}
+	if len(m.Path) < 2 {
+		return fmt.Errorf("move must include its path from origin to destination")
+	}
+	legal := false
+	for _, v := range movesFor(g, mover) {
+		if samePath(v.Path, m.Path) {
+			legal = true
+			break
+		}
+	}
+	if !legal {
+		return fmt.Errorf("illegal move %s", FormatMove(m))
+	}
+
+	from := m.Path[0]
+	dest := m.Path[len(m.Path)-1]
+	cells := pieces(g)
+	_, kind := pieceAt(g, from)
+	captured := false
+	for i := 1; i < len(m.Path); i++ {
+		prev, next := m.Path[i-1], m.Path[i]
+		if abs(next.Row-prev.Row) == 2 {
+			mid := engine.Position{Row: (prev.Row + next.Row) / 2, Col: (prev.Col + next.Col) / 2}
+			if _, err := cells.Remove(mid); err != nil {
+				return err
+			}
+			if _, err := g.Board.Remove(mid); err != nil {
+				return err
+			}
+			captured = true
+		}
+	}
+	if err := cells.Move(from, dest); err != nil {
+		return err
+	}
+	if err := g.Board.Move(from, dest); err != nil {
+		return err
+	}
+	if kind == Man && dest.Row == crownRow(g, mover) {
+		if _, err := cells.Replace(dest, engine.Piece{Owner: mover, Kind: int(King)}); err != nil {
+			return err
+		}
+	}
+	g.Log = append(g.Log, engine.Move{PlayerID: mover, Pos: dest, Path: append([]engine.Position(nil), m.Path...)})
+
+	if captured || kind == Man {
+		g.QuietMoves = 0
+	} else {
+		g.QuietMoves++
+	}
+
+	// A player who cannot move on their turn loses.
+	opponent := opponentOf(g, mover)
+	if len(movesFor(g, opponent)) == 0 {
+		g.EndGame(engine.Outcome{Winner: lookupPlayer(g.Players, mover)})
+	} else if r.DrawMoves > 0 && g.QuietMoves >= r.DrawMoves*len(g.Players) {
+		g.EndGame(engine.Outcome{Draw: true})
+	}
+	return nil
+}
+
+// Status reports whether the current position is terminal.
+func (r Rules) Status(g *engine.Game) (engine.Outcome, bool) {
+	if g.Outcome.Winner != nil || g.Outcome.Draw {
+		return g.Outcome, true
+	}
+	current := g.CurrentPlayer().ID
+	if len(movesFor(g, current)) == 0 {
+		return engine.Outcome{Winner: lookupPlayer(g.Players, opponentOf(g, current))}, true
+	}
+	if r.DrawMoves > 0 && g.QuietMoves >= r.DrawMoves*len(g.Players) {
+		return engine.Outcome{Draw: true}, true
+	}
+	return engine.Outcome{}, false
+}
+
+// RenderBoard draws the board with lowercase tokens for men and uppercase for kings.
+func RenderBoard(g *engine.Game) string {
+	var sb strings.Builder
+	for r := 0; r < g.Board.Rows; r++ {
+		for c := 0; c < g.Board.Cols; c++ {
+			pos := engine.Position{Row: r, Col: c}
+			owner, kind := pieceAt(g, pos)
+			ch := " "
+			if isDark(pos) {
+				ch = "."
+			}
+			if kind != Empty {
+				ch = token(g, owner)
+				if kind == King {
+					ch = strings.ToUpper(ch)
+				} else {
+					ch = strings.ToLower(ch)
+				}
+			}
+			sb.WriteString(ch)
+			if c < g.Board.Cols-1 {
+				sb.WriteByte(' ')
+			}
+		}
+		if r < g.Board.Rows-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// movesFor lists legal moves for a player regardless of whose turn it is.
+func movesFor(g *engine.Game, playerID int) []engine.Move {
+	var captures, steps []engine.Move
+	pieces(g).ForEach(func(pos engine.Position, p engine.Piece) {
+		owner, kind := p.Owner, Kind(p.Kind)
+		if owner != playerID {
+			return
+		}
+		dirs := directions(g, playerID, kind)
+		for _, seq := range jumpSequences(g, pos, playerID, kind, dirs) {
+			captures = append(captures, engine.Move{PlayerID: playerID, Pos: seq[len(seq)-1], Path: seq})
+		}
+		if len(captures) > 0 {
+			return
+		}
+		for _, d := range dirs {
+			next := engine.Position{Row: pos.Row + d.Row, Col: pos.Col + d.Col}
+			if g.Board.InBounds(next) && isEmpty(g, next) {
+				steps = append(steps, engine.Move{PlayerID: playerID, Pos: next, Path: []engine.Position{pos, next}})
+			}
+		}
+	})
+	if len(captures) > 0 {
+		return captures
+	}
+	return steps
+}
+
+// jumpSequences returns every maximal capture path starting at from. Jumped
+// pieces stay on the board until the move completes, so they block landing and
+// cannot be jumped twice.
+func jumpSequences(g *engine.Game, from engine.Position, playerID int, kind Kind, dirs []engine.Position) [][]engine.Position {
+	var out [][]engine.Position
+	jumped := map[engine.Position]bool{}
+	var walk func(path []engine.Position)
+	walk = func(path []engine.Position) {
+		cur := path[len(path)-1]
+		extended := false
+		// A man that is crowned mid-sequence stops there.
+		if len(path) == 1 || kind == King || cur.Row != crownRow(g, playerID) {
+			for _, d := range dirs {
+				mid := engine.Position{Row: cur.Row + d.Row, Col: cur.Col + d.Col}
+				land := engine.Position{Row: cur.Row + 2*d.Row, Col: cur.Col + 2*d.Col}
+				if jumped[mid] {
+					continue
+				}
+				if owner, _ := pieceAt(g, mid); owner == 0 || owner == playerID {
+					continue
+				}
+				if !g.Board.InBounds(land) || (!isEmpty(g, land) && land != from) {
+					continue
+				}
+				jumped[mid] = true
+				walk(append(path[:len(path):len(path)], land))
+				delete(jumped, mid)
+				extended = true
+			}
+		}
+		if !extended && len(path) > 1 {
+			out = append(out, path)
+		}
+	}
+	walk([]engine.Position{from})
+	return out
+}
+
+// pieces returns the game's pieces. A game set up with only a board of
+// owners, such as a test position, gets a man for each owned cell.
+func pieces(g *engine.Game) *engine.Grid[engine.Piece] {
+	if g.Pieces == nil {
+		g.Pieces = engine.NewGrid[engine.Piece](g.Board.Rows, g.Board.Cols)
+		g.Board.ForEach(func(pos engine.Position, owner int) {
+			if owner != 0 {
+				_ = g.Pieces.SetAt(pos, engine.Piece{Owner: owner, Kind: int(Man)})
+			}
+		})
+	}
+	return g.Pieces
+}
+
+// pieceAt returns the owner and kind of the piece at pos, or Empty when
+// there is none or pos is off the board.
+func pieceAt(g *engine.Game, pos engine.Position) (int, Kind) {
+	p, err := pieces(g).Get(pos)
+	if err != nil || p == (engine.Piece{}) {
+		return 0, Empty
+	}
+	return p.Owner, Kind(p.Kind)
+}
+
+func isEmpty(g *engine.Game, pos engine.Position) bool {
+	_, kind := pieceAt(g, pos)
+	return kind == Empty
+}
+
+// directions returns the diagonal steps available to a piece.
+func directions(g *engine.Game, playerID int, kind Kind) []engine.Position {
+	forward := 1
+	if playerID != g.Players[0].ID {
+		forward = -1
+	}
+	dirs := []engine.Position{{Row: forward, Col: -1}, {Row: forward, Col: 1}}
+	if kind == King {
+		dirs = append(dirs, engine.Position{Row: -forward, Col: -1}, engine.Position{Row: -forward, Col: 1})
+	}
+	return dirs
+}
+
+// crownRow returns the row on which a player's men are crowned.
+func crownRow(g *engine.Game, playerID int) int {
+	if playerID == g.Players[0].ID {
+		return g.Board.Rows - 1
+	}
+	return 0
+}
+
+func opponentOf(g *engine.Game, playerID int) int {
+	for _, p := range g.Players {
+		if p.ID != playerID {
+			return p.ID
+		}
+	}
+	return 0
+}
+
+func isDark(pos engine.Position) bool {
+	return (pos.Row+pos.Col)%2 == 1
+}
+
+func samePath(a, b []engine.Position) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func token(g *engine.Game, id int) string {
+	p := lookupPlayer(g.Players, id)
+	if p == nil || p.Token == "" {
+		return fmt.Sprintf("%d", id)
+	}
+	return p.Token
+}
+
+func lookupPlayer(players []engine.Player, id int) *engine.Player {
+	for i := range players {
+		if players[i].ID == id {
+			return &players[i]
+		}
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,171 @@
+package checkers
+
+import (
+	"math/rand"
+	"testing"
+
+	"boardgame/engine"
+)
+
+var testPlayers = []engine.Player{
+	{ID: 1, Name: "Black", Token: "b"},
+	{ID: 2, Name: "White", Token: "w"},
+}
+
+func TestOpeningMoves(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	moves := r.ValidMoves(g)
+	if len(moves) != 7 {
+		t.Fatalf("expected 7 opening moves, got %d", len(moves))
+	}
+	m, err := ParseMove(r, g, "11-15")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := r.ApplyMove(g, m); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := FormatMove(g.Log[0]); got != "11-15" {
+		t.Fatalf("expected 11-15 in log, got %s", got)
+	}
+}
+
+func TestMultiJumpIsMandatoryAndRemovesPieces(t *testing.T) {
+	r := NewRules()
+	g := emptyGame(t)
+	place(t, g, 9, 1, Man)
+	place(t, g, 14, 2, Man)
+	place(t, g, 23, 2, Man)
+	place(t, g, 32, 2, Man)
+	place(t, g, 1, 1, Man)
+
+	moves := r.ValidMoves(g)
+	if len(moves) != 1 {
+		t.Fatalf("expected the capture to be the only move, got %d", len(moves))
+	}
+	if got := FormatMove(moves[0]); got != "9x18x27" {
+		t.Fatalf("expected 9x18x27, got %s", got)
+	}
+	m, err := ParseMove(r, g, "9x27")
+	if err != nil {
+		t.Fatalf("parse short capture: %v", err)
+	}
+	if err := r.ApplyMove(g, m); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	for _, sq := range []int{9, 14, 23} {
+		if p := pieceOn(t, g, sq); p != (engine.Piece{}) {
+			t.Fatalf("expected square %d empty, got %+v", sq, p)
+		}
+	}
+	if p := pieceOn(t, g, 27); p != (engine.Piece{Owner: 1, Kind: int(Man)}) {
+		t.Fatalf("expected man on 27, got %+v", p)
+	}
+}
+
+func TestCrowningEndsTheMove(t *testing.T) {
+	r := NewRules()
+	g := emptyGame(t)
+	place(t, g, 22, 1, Man)
+	place(t, g, 26, 2, Man)
+	place(t, g, 27, 2, Man)
+	place(t, g, 5, 2, Man)
+
+	m, err := ParseMove(r, g, "22x31")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := r.ApplyMove(g, m); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if p := pieceOn(t, g, 31); p != (engine.Piece{Owner: 1, Kind: int(King)}) {
+		t.Fatalf("expected king on 31, got %+v", p)
+	}
+	// The new king could jump 27 as a king, but crowning ends the turn.
+	if p := pieceOn(t, g, 27); p != (engine.Piece{Owner: 2, Kind: int(Man)}) {
+		t.Fatalf("expected 27 untouched, got %+v", p)
+	}
+}
+
+func TestQuietKingMovesDraw(t *testing.T) {
+	r := Rules{DrawMoves: 1}
+	g := emptyGame(t)
+	place(t, g, 1, 1, King)
+	place(t, g, 32, 2, King)
+
+	for _, text := range []string{"1-5", "32-28"} {
+		m, err := ParseMove(r, g, text)
+		if err != nil {
+			t.Fatalf("parse %s: %v", text, err)
+		}
+		if err := r.ApplyMove(g, m); err != nil {
+			t.Fatalf("apply %s: %v", text, err)
+		}
+		g.AdvanceTurn()
+	}
+	if outcome, done := r.Status(g); !done || !outcome.Draw {
+		t.Fatalf("expected draw after quiet king moves, got %+v done=%v", outcome, done)
+	}
+	// ParseMove already consults ValidMoves, so build the king's retreat by hand.
+	from, _ := SquarePos(28)
+	to, _ := SquarePos(32)
+	m := engine.Move{PlayerID: g.CurrentPlayer().ID, Pos: to, Path: []engine.Position{from, to}}
+	if err := r.ApplyMove(g, m); err == nil {
+		t.Fatal("expected a move after the draw to be refused")
+	}
+	if _, kind := pieceAt(g, from); kind != King {
+		t.Fatal("expected the refused move to leave the board unchanged")
+	}
+}
+
+func TestRandomGameFinishes(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	agents := map[int]engine.Agent{1: &engine.RandomAgent{Rand: rng}, 2: &engine.RandomAgent{Rand: rng}}
+	if _, err := engine.Play(g, r, agents); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+}
+
+func emptyGame(t *testing.T) *engine.Game {
+	t.Helper()
+	g, err := engine.NewGame(engine.NewBoard(BoardSize, BoardSize), testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	return g
+}
+
+func place(t *testing.T, g *engine.Game, square, owner int, kind Kind) {
+	t.Helper()
+	pos, err := SquarePos(square)
+	if err != nil {
+		t.Fatalf("square %d: %v", square, err)
+	}
+	if err := g.Board.Set(pos, owner); err != nil {
+		t.Fatalf("place %d: %v", square, err)
+	}
+	if err := pieces(g).SetAt(pos, engine.Piece{Owner: owner, Kind: int(kind)}); err != nil {
+		t.Fatalf("place %d: %v", square, err)
+	}
+}
+
+// pieceOn returns the piece on square, checking that the board agrees on
+// its owner.
+func pieceOn(t *testing.T, g *engine.Game, square int) engine.Piece {
+	t.Helper()
+	pos, _ := SquarePos(square)
+	p, _ := g.Pieces.Get(pos)
+	if owner, _ := g.Board.Get(pos); owner != p.Owner {
+		t.Fatalf("square %d: board owner %d, piece %+v", square, owner, p)
+	}
+	return p
+}
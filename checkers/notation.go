@@ -0,0 +1,112 @@
+package checkers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// Square returns the PDN square number (1-32) for a dark square, or 0 for light squares.
+// Square 1 is the top-left dark square from the first player's side.
+func Square(pos engine.Position) int {
+	if pos.Row < 0 || pos.Row >= BoardSize || pos.Col < 0 || pos.Col >= BoardSize || !isDark(pos) {
+		return 0
+	}
+	return pos.Row*(BoardSize/2) + pos.Col/2 + 1
+}
+
+// SquarePos converts a PDN square number into a board position.
+func SquarePos(square int) (engine.Position, error) {
+	if square < 1 || square > BoardSize*BoardSize/2 {
+		return engine.Position{}, fmt.Errorf("square must be between 1 and %d", BoardSize*BoardSize/2)
+	}
+	idx := square - 1
+	row := idx / (BoardSize / 2)
+	col := (idx % (BoardSize / 2)) * 2
+	if row%2 == 0 {
+		col++
+	}
+	return engine.Position{Row: row, Col: col}, nil
+}
+
+// FormatMove prints a move in PDN style: "11-15" for steps and "22x15x6" for captures.
+func FormatMove(m engine.Move) string {
+	path := m.Path
+	if len(path) == 0 {
+		return strconv.Itoa(Square(m.Pos))
+	}
+	sep := "-"
+	if len(path) > 1 && abs(path[1].Row-path[0].Row) == 2 {
+		sep = "x"
+	}
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(Square(p))
+	}
+	return strings.Join(parts, sep)
+}
+
+// Notation is PDN move notation for use through engine.Notation; see
+// ParseMove and FormatMove.
+type Notation struct {
+	Rules Rules
+}
+
+// ParseMove implements engine.Notation.
+func (n Notation) ParseMove(g *engine.Game, text string) (engine.Move, error) {
+	return ParseMove(n.Rules, g, text)
+}
+
+// FormatMove implements engine.Notation.
+func (Notation) FormatMove(_ *engine.Game, m engine.Move) string {
+	return FormatMove(m)
+}
+
+// ParseMove reads PDN move text and resolves it against the current player's
+// valid moves. Captures may list every landing square ("22x15x6") or only the
+// start and end ("22x6") when that is unambiguous.
+func ParseMove(r Rules, g *engine.Game, text string) (engine.Move, error) {
+	s := strings.TrimSpace(text)
+	sep := "-"
+	if strings.ContainsAny(s, "xX") {
+		sep = "x"
+		s = strings.ReplaceAll(s, "X", "x")
+	}
+	fields := strings.Split(s, sep)
+	if len(fields) < 2 {
+		return engine.Move{}, fmt.Errorf("move %q must include origin and destination squares", text)
+	}
+	squares := make([]engine.Position, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return engine.Move{}, fmt.Errorf("invalid square %q", f)
+		}
+		pos, err := SquarePos(n)
+		if err != nil {
+			return engine.Move{}, err
+		}
+		squares[i] = pos
+	}
+
+	var matches []engine.Move
+	for _, m := range r.ValidMoves(g) {
+		capture := abs(m.Path[1].Row-m.Path[0].Row) == 2
+		if capture != (sep == "x") {
+			continue
+		}
+		if samePath(m.Path, squares) || (len(squares) == 2 && m.Path[0] == squares[0] && m.Pos == squares[1]) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return engine.Move{}, fmt.Errorf("move %q is not legal here", text)
+	case 1:
+		return matches[0], nil
+	default:
+		return engine.Move{}, fmt.Errorf("move %q is ambiguous; list every landing square", text)
+	}
+}
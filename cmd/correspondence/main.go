@@ -0,0 +1,162 @@
+// Command correspondence manages slow Go games played over days. Each
+// invocation performs one command and exits; run "check" periodically (from
+// cron, say) to end games whose deadline has passed and send reminders.
+// Notifications are appended to a log file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"boardgame/correspondence"
+	"boardgame/gameapi"
+	"boardgame/gogame"
+)
+
+const usage = `usage: correspondence [flags] command [args]
+
+commands:
+  new <black> <white>             start a game (see -size, -days, -vacation, -komi)
+  play <id> <player> <move>       play a coordinate such as D4, "pass", or "resign"
+  vacation <id> <player> on|off   start or end vacation in a game
+  show <id>                       print the board and deadlines
+  list                            list games
+  check                           end overdue games and send reminders
+
+flags:
+`
+
+func main() {
+	dir := flag.String("dir", defaultDir(), "directory where games are stored")
+	logPath := flag.String("log", "", "notification log (defaults to notifications.log in -dir)")
+	size := flag.Int("size", 19, "board size for new games")
+	days := flag.Int("days", 3, "days allowed per move in new games")
+	vacation := flag.Int("vacation", 14, "vacation days per player in new games")
+	komi := flag.Float64("komi", 6.5, "komi for new games")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	store, err := correspondence.NewFileStore(*dir)
+	if err != nil {
+		fail(err)
+	}
+	if *logPath == "" {
+		*logPath = filepath.Join(*dir, "notifications.log")
+	}
+	logFile, err := os.OpenFile(*logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		fail(err)
+	}
+	defer logFile.Close()
+	m := correspondence.NewManager(store, &correspondence.LogNotifier{W: logFile})
+
+	args := flag.Args()
+	switch cmd := args[0]; {
+	case cmd == "new" && len(args) == 3:
+		cg, err := m.Create(args[1], args[2], correspondence.Settings{Size: *size, Komi: *komi, MoveDays: *days, VacationDays: *vacation})
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("Started game %s: %s (Black) vs %s (White).\n", cg.ID, cg.Black.Name, cg.White.Name)
+	case cmd == "play" && len(args) == 4:
+		action := gameapi.Action{Type: strings.ToLower(args[3])}
+		if action.Type != "pass" && action.Type != "resign" {
+			action = gameapi.Action{Type: "play", Coord: args[3]}
+		}
+		cg, msg, err := m.Play(args[1], args[2], action)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Println(msg)
+		show(cg, m.Now())
+	case cmd == "vacation" && len(args) == 4 && (args[3] == "on" || args[3] == "off"):
+		cg, err := m.SetVacation(args[1], args[2], args[3] == "on")
+		if err != nil {
+			fail(err)
+		}
+		p := cg.Player(cg.ColorOf(args[2]))
+		state := "back"
+		if p.OnVacation {
+			state = "on vacation"
+		}
+		fmt.Printf("%s is %s, with %s of vacation left.\n", p.Name, state, formatDays(p.VacationLeft))
+	case cmd == "show" && len(args) == 2:
+		cg, err := m.Load(args[1])
+		if err != nil {
+			fail(err)
+		}
+		show(cg, m.Now())
+	case cmd == "list" && len(args) == 1:
+		games, err := m.Store.List()
+		if err != nil {
+			fail(err)
+		}
+		for _, cg := range games {
+			fmt.Printf("%s  %s vs %s, %d moves, %s\n", cg.ID, cg.Black.Name, cg.White.Name, cg.Game.MoveNumber(), status(cg, m.Now()))
+		}
+	case cmd == "check" && len(args) == 1:
+		timedOut, err := m.CheckDeadlines()
+		if err != nil {
+			fail(err)
+		}
+		for _, cg := range timedOut {
+			fmt.Printf("Game %s ended on time: %s.\n", cg.ID, cg.Result)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func show(cg *correspondence.Game, now time.Time) {
+	fmt.Println(gogame.RenderBoardASCII(cg.Game))
+	fmt.Printf("%s (Black) vs %s (White): %s.\n", cg.Black.Name, cg.White.Name, status(cg, now))
+}
+
+func status(cg *correspondence.Game, now time.Time) string {
+	if cg.Over() {
+		return "result " + cg.Result
+	}
+	p := cg.ToPlay()
+	s := fmt.Sprintf("%s to play, %s left", p.Name, formatDays(cg.TimeLeft(now)))
+	if p.OnVacation {
+		s += fmt.Sprintf(" (on vacation, %s of it left)", formatDays(p.VacationLeft))
+	}
+	return s
+}
+
+// formatDays formats a duration in days, to the hour.
+func formatDays(d time.Duration) string {
+	d = d.Round(time.Hour)
+	n := int(d / correspondence.Day)
+	h := int((d % correspondence.Day) / time.Hour)
+	if h == 0 {
+		return strconv.Itoa(n) + "d"
+	}
+	return fmt.Sprintf("%dd%dh", n, h)
+}
+
+func defaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "correspondence"
+	}
+	return filepath.Join(dir, "boardgame", "correspondence")
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "correspondence: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"boardgame/server"
+	"boardgame/storage"
+	"boardgame/web"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	webDir := flag.String("web", "", "serve the web client from this directory instead of the built-in copy")
+	storeDir := flag.String("store", "", "save games in this directory so they survive restarts")
+	origins := flag.String("origins", "", "comma-separated origins of other sites whose pages may join rooms, e.g. https://example.com")
+	flag.Parse()
+
+	var ui fs.FS = web.FS
+	if *webDir != "" {
+		ui = os.DirFS(*webDir)
+	}
+	var store storage.Store
+	if *storeDir != "" {
+		fileStore, err := storage.NewFileStore(*storeDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		store = fileStore
+	}
+	srv := server.New(ui, store)
+	if *origins != "" {
+		srv.AllowedOrigins = strings.Split(*origins, ",")
+	}
+	fmt.Printf("Serving Go games on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
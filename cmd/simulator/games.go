@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"boardgame/engine"
+
+	// Rules packages register their games with engine.
+	_ "boardgame/checkers"
+	_ "boardgame/tictactoe"
+	_ "boardgame/ultimate"
+)
+
+// gameNames lists the registered games for help text.
+func gameNames() string {
+	var names []string
+	for _, def := range engine.Games() {
+		names = append(names, def.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func lookupGame(name string) (engine.GameDefinition, error) {
+	def, ok := engine.Lookup(name)
+	if !ok {
+		return engine.GameDefinition{}, fmt.Errorf("unknown game %q (want %s)", name, gameNames())
+	}
+	return def, nil
+}
+
+// newAgents seats players from a comma-separated list of "human" or
+// "random" in turn order, taking the game's default players in order. Humans
+// get a nil agent.
+func newAgents(def engine.GameDefinition, list string, r *rand.Rand) ([]engine.Player, map[int]engine.Agent, error) {
+	specs := strings.Split(list, ",")
+	if len(specs) < def.MinPlayers || len(specs) > len(def.Players) {
+		want := fmt.Sprintf("%d to %d", def.MinPlayers, len(def.Players))
+		if def.MinPlayers == len(def.Players) {
+			want = fmt.Sprint(def.MinPlayers)
+		}
+		return nil, nil, fmt.Errorf("%s needs %s players, got %q", def.Title, want, list)
+	}
+	players := def.Players[:len(specs)]
+	agents := map[int]engine.Agent{}
+	for i, spec := range specs {
+		switch strings.ToLower(strings.TrimSpace(spec)) {
+		case "human":
+			agents[players[i].ID] = nil
+		case "random":
+			agents[players[i].ID] = &engine.RandomAgent{Rand: r}
+		default:
+			return nil, nil, fmt.Errorf("unknown player %q (want human or random)", spec)
+		}
+	}
+	return players, agents, nil
+}
+
+// playGame runs "<game> play": a game of def between people at the keyboard
+// and random agents.
+func playGame(def engine.GameDefinition, args []string) (err error) {
+	flags := newFlags(def.Name+" play", "")
+	list := flags.String("players", strings.TrimSuffix(strings.Repeat("human,", len(def.Players)), ","),
+		"comma-separated players in turn order: human or random")
+	save := flags.String("save", "", "write the moves to this file as a JSON record when the game ends, for replay")
+	parseFlags(flags, args, 0)
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // game play, not security
+	players, agents, err := newAgents(def, *list, r)
+	if err != nil {
+		return err
+	}
+	g, err := def.NewGame(players)
+	if err != nil {
+		return err
+	}
+	if *save != "" {
+		defer func() {
+			if saveErr := saveRecord(*save, def, g); err == nil {
+				err = saveErr
+			}
+		}()
+	}
+	fmt.Printf("%s. Enter moves as %s; 'quit' to exit.\n", def.Title, def.MoveHelp)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("\n%s\n", def.Render(g))
+		if outcome, done := def.Rule.Status(g); done {
+			printOutcome(outcome)
+			return nil
+		}
+		moves := def.Rule.ValidMoves(g)
+		if len(moves) == 0 {
+			printOutcome(engine.Outcome{Draw: true})
+			return nil
+		}
+		p := g.CurrentPlayer()
+		var m engine.Move
+		if agent := agents[p.ID]; agent != nil {
+			if m, err = agent.ChooseMove(g, moves); err != nil {
+				return err
+			}
+			fmt.Printf("%s (random) plays %s.\n", p.Name, def.Notation.FormatMove(g, m))
+		} else {
+			fmt.Printf("Move %d - %s to play: ", len(g.Log)+1, p.Name)
+			raw, readErr := reader.ReadString('\n')
+			if readErr != nil && raw == "" {
+				fmt.Println("\nExiting.")
+				return nil
+			}
+			input := strings.TrimSpace(raw)
+			if input == "q" || input == "quit" || input == "exit" {
+				fmt.Println("Exiting.")
+				return nil
+			}
+			if m, err = def.Notation.ParseMove(g, input); err != nil {
+				fmt.Printf("Invalid move: %v\n", err)
+				continue
+			}
+		}
+		if err := def.Rule.ApplyMove(g, m); err != nil {
+			fmt.Printf("Invalid move: %v\n", err)
+			continue
+		}
+		g.AdvanceTurn()
+	}
+}
+
+// saveRecord writes the moves of g so far to file as a JSON record.
+func saveRecord(file string, def engine.GameDefinition, g *engine.Game) error {
+	data, err := json.MarshalIndent(engine.NewRecord(def, g), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+func printOutcome(o engine.Outcome) {
+	if o.Winner != nil {
+		fmt.Printf("%s wins. Game over.\n", o.Winner.Name)
+		return
+	}
+	fmt.Println("Draw. Game over.")
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"boardgame/gogame"
+)
+
+// goPlay runs "go play": a game of Go between people at the keyboard and
+// agents, saved after every move.
+func goPlay(args []string) error {
+	flags := newFlags("go play", "")
+	size := flags.Int("size", 9, "board size (commonly 9, 13, or 19)")
+	cols := flags.Int("cols", 0, "board width for rectangular boards (defaults to -size)")
+	variantName := flags.String("variant", "standard", "rules variant: standard or atari (first capture wins)")
+	captureGoal := flags.Int("captures", 1, "stones a player must capture to win the atari variant")
+	komi := flags.Float64("komi", 6.5, "points added to White's score")
+	mainTime := flags.Duration("time", 0, "main time per player, e.g. 10m (0 disables the clock)")
+	timeSystem := flags.String("timesystem", "absolute", "time system: absolute, fischer, canadian, or byoyomi")
+	increment := flags.Duration("increment", 0, "Fischer increment added after each move")
+	period := flags.Duration("period", 30*time.Second, "Canadian or byo-yomi overtime period length")
+	periods := flags.Int("periods", 5, "number of byo-yomi periods")
+	stones := flags.Int("stones", 10, "moves required per Canadian overtime period")
+	storeDir := flags.String("store", defaultStoreDir(), "directory where games are saved after every move (empty disables saving)")
+	resumeID := flags.String("resume", "", "resume the saved game with this ID (see the 'games' command)")
+	lineMode := flags.Bool("line", false, "use the line-based interface even on a terminal")
+	blackSpec := flags.String("black", "human", "who plays Black: "+playerSpecs)
+	whiteSpec := flags.String("white", "human", "who plays White: "+playerSpecs)
+	hintSpec := flags.String("hint", "mcts", "agent that answers the 'hint' command: "+playerSpecs)
+	parseFlags(flags, args, 0)
+
+	players, err := newLineup(*blackSpec, *whiteSpec, *hintSpec, *komi)
+	if err != nil {
+		return fmt.Errorf("cannot start players: %w", err)
+	}
+	defer players.close()
+	saves := newSaver(*storeDir)
+	if *resumeID != "" {
+		game, err := saves.load(*resumeID)
+		if err != nil {
+			return fmt.Errorf("cannot resume game: %w", err)
+		}
+		run(game, saves, players, *komi, *lineMode)
+		return nil
+	}
+
+	variant, err := gogame.ParseVariant(*variantName)
+	if err != nil {
+		return fmt.Errorf("cannot start game: %w", err)
+	}
+	if *cols == 0 {
+		*cols = *size
+	}
+	game, err := gogame.NewRectGame(*size, *cols)
+	if err != nil {
+		return fmt.Errorf("cannot start game: %w", err)
+	}
+	game.Variant = variant
+	game.CaptureGoal = *captureGoal
+	saves.start(game)
+	if *mainTime > 0 {
+		system, err := gogame.ParseTimeSystem(*timeSystem)
+		if err != nil {
+			return fmt.Errorf("cannot start game: %w", err)
+		}
+		game.StartClock(gogame.TimeControl{
+			System:    system,
+			MainTime:  *mainTime,
+			Increment: *increment,
+			Period:    *period,
+			Periods:   *periods,
+			Stones:    *stones,
+		}, nil)
+	}
+	run(game, saves, players, *komi, *lineMode)
+	return nil
+}
+
+// run plays game in the full-screen interface when attached to a terminal,
+// and line by line otherwise.
+func run(game *gogame.Game, saves *saver, players *lineup, komi float64, lineMode bool) {
+	if !lineMode && useTUI() {
+		if err := playTUI(game, saves, players, komi); err == nil {
+			return
+		}
+	}
+	play(game, saves, players, komi)
+}
+
+// play runs the interactive loop for game until it ends or the user quits,
+// saving it after every change. Agents move by themselves on their turns.
+func play(game *gogame.Game, saves *saver, players *lineup, komi float64) {
+	fmt.Printf("Go game on %dx%d board. Coordinates like D4, row numbers from bottom.\n", game.Cols, game.Rows)
+	if game.Variant == gogame.AtariGo {
+		fmt.Printf("Atari Go: the first player to capture %d stone(s) wins.\n", max(game.CaptureGoal, 1))
+	}
+	fmt.Printf("Black: %s. White: %s.\n", players.black.spec, players.white.spec)
+	fmt.Println("Commands: coordinate to play, 'pass' to pass, 'undo', 'hint', 'score', 'estimate', 'resign', 'sgf' to print the record, 'quit' to exit.")
+	fmt.Println("Saved games: 'save' to save now, 'games' to list them, 'load <id>' to resume one.")
+	if saves.store != nil {
+		fmt.Printf("This game is saved automatically as %s.\n", saves.id)
+	}
+	printBoard(game)
+	defer func() { saves.autosave(game) }()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		saves.autosave(game)
+		if p, ok := players.agentToMove(game); ok {
+			fmt.Printf("\n%s (%s) is thinking...\n", game.ToPlay, p.spec)
+			msg, err := agentMove(game, p)
+			if err != nil {
+				fmt.Printf("%v\n", err)
+				return
+			}
+			fmt.Println(msg)
+			if game.Result != "" {
+				printResult(game)
+				return
+			}
+			printBoard(game)
+			if game.ConsecutivePasses >= 2 {
+				fmt.Println("Both players passed. Game over.")
+				printScore(game, komi)
+				return
+			}
+			continue
+		}
+		fmt.Printf("\nMove %d - %s to play: ", game.MoveNumber()+1, game.ToPlay)
+		raw, readErr := reader.ReadString('\n')
+		if readErr != nil && raw == "" {
+			fmt.Println("\nExiting.")
+			return
+		}
+		input := strings.TrimSpace(strings.ToLower(raw))
+		if err := game.CheckTime(); err != nil {
+			fmt.Printf("%v.\n", err)
+			printResult(game)
+			return
+		}
+		if id, ok := strings.CutPrefix(input, "load "); ok {
+			loaded, err := saves.load(strings.TrimSpace(id))
+			if err != nil {
+				fmt.Printf("Cannot load game: %v\n", err)
+				continue
+			}
+			game = loaded
+			fmt.Printf("Resumed game %s.\n", saves.id)
+			printBoard(game)
+			continue
+		}
+		switch input {
+		case "q", "quit", "exit":
+			fmt.Println("Exiting.")
+			return
+		case "save":
+			if err := saves.save(game); err != nil {
+				fmt.Printf("Cannot save: %v\n", err)
+				continue
+			}
+			fmt.Printf("Saved as %s; resume with 'load %s' or -resume %s.\n", saves.id, saves.id, saves.id)
+			continue
+		case "games":
+			saves.printList()
+			continue
+		case "hint":
+			_, msg, err := players.hintFor(game)
+			if err != nil {
+				fmt.Printf("No hint: %v\n", err)
+				continue
+			}
+			fmt.Println(msg)
+			continue
+		case "sgf":
+			fmt.Println(gogame.WriteSGF(game))
+			continue
+		case "estimate":
+			estimate := gogame.EstimateOwnership(game, komi)
+			fmt.Println(gogame.RenderOwnershipASCII(game, estimate))
+			fmt.Printf("Estimated result (komi %.1f): %s. Legend: x/o territory, ,/' leaning, ! likely dead.\n", komi, estimate.Result())
+			continue
+		case "score":
+			printScore(game, komi)
+			continue
+		case "undo":
+			if err := players.undo(game); err != nil {
+				fmt.Printf("Cannot undo: %v\n", err)
+				continue
+			}
+			fmt.Println("Took back the last move.")
+			printBoard(game)
+			continue
+		case "resign":
+			resigner := game.ToPlay
+			if err := game.Resign(resigner); err != nil {
+				fmt.Printf("Cannot resign: %v\n", err)
+				continue
+			}
+			fmt.Printf("%s resigned.\n", resigner)
+			printResult(game)
+			return
+		case "pass":
+			game.Pass()
+			if game.Result != "" {
+				printResult(game)
+				return
+			}
+			fmt.Println("Player passed.")
+			if game.ConsecutivePasses >= 2 {
+				fmt.Println("Both players passed. Game over.")
+				printBoard(game)
+				printScore(game, komi)
+				return
+			}
+			printBoard(game)
+			continue
+		}
+
+		m, err := notation.ParseMove(game, input)
+		if err != nil {
+			fmt.Printf("Invalid move: %v\n", err)
+			continue
+		}
+		result, err := game.Play(m)
+		if err != nil {
+			fmt.Printf("Invalid move: %v\n", err)
+			continue
+		}
+		if result.Captured > 0 {
+			fmt.Printf("Captured %d stones.\n", result.Captured)
+		}
+		printBoard(game)
+		if winner, over := game.Status(); over && winner != gogame.None {
+			printResult(game)
+			return
+		}
+	}
+}
+
+// printResult announces a decided game and prints its SGF record.
+func printResult(game *gogame.Game) {
+	reason := "by capture"
+	switch {
+	case strings.HasSuffix(game.Result, "+R"):
+		reason = "by resignation"
+	case strings.HasSuffix(game.Result, "+T"):
+		reason = "on time"
+	}
+	fmt.Printf("%s wins %s (%s). Game over.\n", game.Winner, reason, game.Result)
+	fmt.Println(gogame.WriteSGF(game))
+}
+
+// printScore shows an area count, with stones in pass-alive territory removed as dead.
+func printScore(game *gogame.Game, komi float64) {
+	score := gogame.ScoreArea(game, komi)
+	fmt.Printf("Score (area, komi %.1f) - Black: %.1f, White: %.1f. Result: %s.\n", komi, score.Black, score.White, score.Result())
+	if len(score.Dead) > 0 {
+		fmt.Printf("Removed %d dead stones in pass-alive territory.\n", len(score.Dead))
+	}
+}
+
+func printBoard(game *gogame.Game) {
+	fmt.Println(gogame.RenderBoardASCII(game))
+	fmt.Printf("Captures - Black: %d, White: %d. To play: %s.\n", game.Captures[gogame.Black], game.Captures[gogame.White], game.ToPlay)
+	if game.Clock != nil {
+		fmt.Printf("Clock - Black: %s, White: %s.\n", formatClock(game.Clock.Remaining(gogame.Black)), formatClock(game.Clock.Remaining(gogame.White)))
+	}
+}
+
+// formatClock shows main time, or the current overtime period and what remains of it.
+func formatClock(pc gogame.PlayerClock) string {
+	left := pc.Left().Round(time.Second)
+	switch {
+	case pc.Flagged:
+		return "out of time"
+	case !pc.Overtime:
+		return left.String()
+	case pc.StonesLeft > 0:
+		return fmt.Sprintf("%s for %d stones", left, pc.StonesLeft)
+	default:
+		return fmt.Sprintf("%s (%d periods)", left, pc.PeriodsLeft)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"boardgame/gtp"
+)
+
+// goGTP runs "go gtp": an agent answering GTP on stdin and stdout, for use
+// with GUIs such as Sabaki or to play other engines.
+func goGTP(args []string) error {
+	flags := newFlags("go gtp", "")
+	spec := flags.String("agent", "mcts", "agent that chooses the moves: "+playerSpecs)
+	komi := flags.Float64("komi", 6.5, "komi until the controller sets one")
+	parseFlags(flags, args, 0)
+
+	c, err := newContestant(*spec, *komi)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+	if c.agent == nil {
+		return errors.New("a GTP engine needs an agent, not a human")
+	}
+	s := &gtp.Server{Agent: c.agent, Name: "simulator", Version: *spec, Komi: *komi}
+	return s.Serve(os.Stdin, os.Stdout)
+}
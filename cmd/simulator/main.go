@@ -1,68 +1,149 @@
+// Command simulator plays board games in the terminal, runs agents against
+// each other, and serves the web client. Run it without arguments to play Go.
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
 
-	"boardgame/gogame"
+	"boardgame/engine"
 )
 
-func main() {
-	size := flag.Int("size", 9, "board size (commonly 9, 13, or 19)")
-	flag.Parse()
+// command is one subcommand. Go has several; the other games in the
+// registry each get "<game> play".
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"go play", "", "play Go against people or agents (the default)", goPlay},
+	{"go gtp", "", "run a Go agent as a GTP engine on stdin and stdout", goGTP},
+	{"go puzzles", "DIR", "drill the SGF life-and-death problems in DIR (or: simulator -puzzles DIR)", goPuzzles},
+	{"simulate", "", "play games between random agents and report the results", simulate},
+	{"tournament", "PLAYER...", "play a round robin between Go agents", tournament},
+	{"replay", "FILE", "step through an SGF record or a saved game", replay},
+	{"serve", "", "serve the web client and its API over HTTP", serve},
+}
 
-	game, err := gogame.NewGame(*size)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "cannot start game: %v\n", err)
+func main() {
+	args := commandArgs(os.Args[1:])
+	if isHelp(args[0]) {
+		printUsage()
+		return
+	}
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "simulator: unknown command %q\n\n", strings.Join(args[:min(2, len(args))], " "))
+		printUsage()
+		os.Exit(2)
+	}
+	if err := cmd.run(rest); err != nil {
+		fmt.Fprintf(os.Stderr, "simulator %s: %v\n", cmd.name, err)
 		os.Exit(1)
 	}
+}
 
-	fmt.Printf("Go game on %dx%d board. Coordinates like D4, row numbers from bottom.\n", *size, *size)
-	fmt.Println("Commands: coordinate to play, 'pass' to pass, 'quit' to exit.")
-	printBoard(game)
+// commandArgs rewrites the command line from before there were subcommands
+// into its subcommand form. The result is never empty.
+func commandArgs(args []string) []string {
+	if alias, ok := puzzlesAlias(args); ok {
+		return alias
+	}
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelp(args[0])) {
+		// Plain "simulator [flags]" still starts a game of Go.
+		return append([]string{"go", "play"}, args...)
+	}
+	return args
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Printf("\nMove %d - %s to play: ", game.MoveNumber()+1, game.ToPlay)
-		raw, _ := reader.ReadString('\n')
-		input := strings.TrimSpace(strings.ToLower(raw))
-		switch input {
-		case "q", "quit", "exit":
-			fmt.Println("Exiting.")
-			return
-		case "pass":
-			game.Pass()
-			fmt.Println("Player passed.")
-			if game.ConsecutivePasses >= 2 {
-				fmt.Println("Both players passed. Game over.")
-				printBoard(game)
-				return
-			}
-			printBoard(game)
-			continue
+// findCommand matches the leading words of args to a command and returns the
+// arguments that follow them.
+func findCommand(args []string) (command, []string, bool) {
+	for _, c := range allCommands() {
+		words := strings.Fields(c.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == c.name {
+			return c, args[len(words):], true
 		}
+	}
+	return command{}, nil, false
+}
 
-		pos, err := gogame.ParseCoord(input, game.Size)
-		if err != nil {
-			fmt.Printf("Invalid move: %v\n", err)
-			continue
-		}
-		result, err := game.PlayMove(pos)
-		if err != nil {
-			fmt.Printf("Invalid move: %v\n", err)
+// allCommands returns the fixed commands followed by "<game> play" for each
+// registered game.
+func allCommands() []command {
+	out := append([]command(nil), commands...)
+	for _, def := range engine.Games() {
+		def := def
+		out = append(out, command{
+			name:    def.Name + " play",
+			summary: "play " + def.Title,
+			run:     func(args []string) error { return playGame(def, args) },
+		})
+	}
+	return out
+}
+
+// puzzlesAlias turns "simulator -puzzles DIR [flags]", from before there
+// were subcommands, into "go puzzles [flags] DIR".
+func puzzlesAlias(args []string) ([]string, bool) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-") {
+		return nil, false // a command, not the old flags
+	}
+	for i, arg := range args {
+		name, dir, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "puzzles" {
 			continue
 		}
-		if result.Captured > 0 {
-			fmt.Printf("Captured %d stones.\n", result.Captured)
+		rest := append([]string{"go", "puzzles"}, args[:i]...)
+		if !hasValue {
+			if i+1 == len(args) {
+				return rest, true // "go puzzles" reports the missing DIR
+			}
+			dir, i = args[i+1], i+1
 		}
-		printBoard(game)
+		rest = append(rest, args[i+1:]...)
+		return append(rest, dir), true
 	}
+	return nil, false
 }
 
-func printBoard(game *gogame.Game) {
-	fmt.Println(gogame.RenderBoardASCII(game))
-	fmt.Printf("Captures - Black: %d, White: %d. To play: %s.\n", game.Captures[gogame.Black], game.Captures[gogame.White], game.ToPlay)
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: simulator command [flags] [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range allCommands() {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "simulator command -h" for the flags of a command.`)
+}
+
+func isHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// newFlags returns the flag set for a command; args describes its
+// positional arguments in the usage message.
+func newFlags(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet("simulator "+name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: simulator %s\n", strings.TrimSpace(name+" [flags] "+args))
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args, exiting with the usage message unless exactly
+// want positional arguments remain (or at least one, when want is negative).
+func parseFlags(flags *flag.FlagSet, args []string, want int) {
+	_ = flags.Parse(args) // exits on error
+	if (want >= 0 && flags.NArg() != want) || (want < 0 && flags.NArg() == 0) {
+		flags.Usage()
+		os.Exit(2)
+	}
 }
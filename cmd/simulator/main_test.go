@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandRouting(t *testing.T) {
+	cases := []struct {
+		args string
+		cmd  string
+		rest []string
+	}{
+		{"", "go play", []string{}},
+		{"-size 9", "go play", []string{"-size", "9"}},
+		{"go play -size 13", "go play", []string{"-size", "13"}},
+		{"go gtp -agent mcts", "go gtp", []string{"-agent", "mcts"}},
+		{"go puzzles problems", "go puzzles", []string{"problems"}},
+		{"checkers play", "checkers play", []string{}},
+		{"ttt play -x human", "ttt play", []string{"-x", "human"}},
+		{"ultimate play", "ultimate play", []string{}},
+		{"-h", "", nil},
+		{"go", "", nil},
+		{"chess play", "", nil},
+	}
+	for _, c := range cases {
+		cmd, rest, ok := findCommand(commandArgs(strings.Fields(c.args)))
+		if c.cmd == "" {
+			if ok {
+				t.Errorf("%q: expected no command, got %q", c.args, cmd.name)
+			}
+			continue
+		}
+		if !ok || cmd.name != c.cmd || !reflect.DeepEqual(rest, c.rest) {
+			t.Errorf("%q: got %q %q (found %v), want %q %q", c.args, cmd.name, rest, ok, c.cmd, c.rest)
+		}
+	}
+}
+
+func TestPuzzlesAlias(t *testing.T) {
+	cases := []struct {
+		args string
+		want string // "" when the arguments are not the old -puzzles form
+	}{
+		{"-puzzles problems", "go puzzles problems"},
+		{"--puzzles problems", "go puzzles problems"},
+		{"-puzzles=problems -seed 3", "go puzzles -seed 3 problems"},
+		{"-seed 3 -puzzles problems -v", "go puzzles -seed 3 -v problems"},
+		{"-puzzles", "go puzzles"},
+		{"-size 9", ""},
+		{"go puzzles problems", ""},
+		{"replay -puzzles", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		got, ok := puzzlesAlias(strings.Fields(c.args))
+		if c.want == "" {
+			if ok {
+				t.Errorf("%q: expected no rewrite, got %q", c.args, got)
+			}
+			continue
+		}
+		if !ok || strings.Join(got, " ") != c.want {
+			t.Errorf("%q: got %q (rewritten %v), want %q", c.args, got, ok, c.want)
+		}
+	}
+}
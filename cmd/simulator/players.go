@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"boardgame/gogame"
+	"boardgame/gtp"
+)
+
+const playerSpecs = "human, random, mcts[:playouts], or gtp:command"
+
+// hintTime caps how long an MCTS hint searches, since the interface waits
+// for it.
+const hintTime = 3 * time.Second
+
+// notation is how Go moves are typed and printed.
+var notation gogame.GTPNotation
+
+// contestant is who plays one color, or gives hints: an agent described by
+// its spec, or a human at the keyboard when agent is nil.
+type contestant struct {
+	spec  string
+	agent gogame.Agent
+	close func()
+}
+
+// lineup is the two players of a game and the agent that gives hints.
+type lineup struct {
+	black, white, hint contestant
+}
+
+// newContestant starts the player a spec describes: "human", "random",
+// "mcts" (playouts scaled to the board) or "mcts:5000" (playouts per move),
+// or "gtp:gnugo --mode gtp".
+func newContestant(spec string, komi float64) (contestant, error) {
+	c := contestant{spec: spec, close: func() {}}
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch strings.ToLower(kind) {
+	case "human":
+	case "random":
+		c.agent = &gogame.PlayoutAgent{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))} //nolint:gosec // game play, not security
+	case "mcts":
+		playouts := 0
+		if arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				return c, fmt.Errorf("invalid playout count in %q", spec)
+			}
+			playouts = n
+		}
+		c.agent = &gogame.MCTSAgent{Playouts: playouts, Komi: komi}
+	case "gtp":
+		engine, err := gtp.Start(arg)
+		if err != nil {
+			return c, err
+		}
+		engine.Komi = komi
+		c.agent = engine
+		c.close = func() { _ = engine.Close() }
+	default:
+		return c, fmt.Errorf("unknown player %q (want %s)", spec, playerSpecs)
+	}
+	return c, nil
+}
+
+func newLineup(black, white, hint string, komi float64) (*lineup, error) {
+	l := &lineup{}
+	var err error
+	if l.black, err = newContestant(black, komi); err != nil {
+		return nil, err
+	}
+	if l.white, err = newContestant(white, komi); err != nil {
+		l.black.close()
+		return nil, err
+	}
+	if l.hint, err = newContestant(hint, komi); err != nil {
+		l.black.close()
+		l.white.close()
+		return nil, err
+	}
+	if a, ok := l.hint.agent.(*gogame.MCTSAgent); ok {
+		a.Time = hintTime
+	}
+	return l, nil
+}
+
+func (l *lineup) close() {
+	l.black.close()
+	l.white.close()
+	l.hint.close()
+}
+
+// player returns who plays color c; humans are returned too, with a nil agent.
+func (l *lineup) player(c gogame.Color) contestant {
+	if c == gogame.White {
+		return l.white
+	}
+	return l.black
+}
+
+// agentToMove returns the agent whose turn it is in g, or nil if a human is
+// to move or the game is over.
+func (l *lineup) agentToMove(g *gogame.Game) (contestant, bool) {
+	if _, over := g.Status(); over {
+		return contestant{}, false
+	}
+	p := l.player(g.ToPlay)
+	return p, p.agent != nil
+}
+
+// think asks an agent for a move in g, timing it. Agents work on a copy so
+// that a misbehaving one cannot change the game.
+func think(agent gogame.Agent, g *gogame.Game) (gogame.Move, time.Duration, error) {
+	start := time.Now()
+	m, err := agent.GenMove(g.Clone())
+	return m, time.Since(start), err
+}
+
+// agentMove asks the agent to move for the side to play and makes the move,
+// resigning for it if its engine resigned. It returns a description of what
+// happened.
+func agentMove(g *gogame.Game, p contestant) (string, error) {
+	mover := g.ToPlay
+	m, took, err := think(p.agent, g)
+	if errors.Is(err, gtp.ErrResign) {
+		_ = g.Resign(mover)
+		return fmt.Sprintf("%s (%s) resigned after %s.", mover, p.spec, formatThinking(took)), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s (%s) failed to move: %w", mover, p.spec, err)
+	}
+	res, err := g.Play(m)
+	if err != nil {
+		return "", fmt.Errorf("%s (%s) chose an illegal move %s: %w", mover, p.spec, notation.FormatMove(g, m), err)
+	}
+	msg := fmt.Sprintf("%s (%s) plays %s in %s.", mover, p.spec, notation.FormatMove(g, m), formatThinking(took))
+	if res.Captured > 0 {
+		msg += fmt.Sprintf(" Captured %d stones.", res.Captured)
+	}
+	return msg, nil
+}
+
+// hintFor asks the hint agent what it would play for the side to move.
+func (l *lineup) hintFor(g *gogame.Game) (gogame.Move, string, error) {
+	if l.hint.agent == nil {
+		return gogame.Move{}, "", errors.New("no hint agent (see -hint)")
+	}
+	if _, over := g.Status(); over {
+		return gogame.Move{}, "", errors.New("the game is over")
+	}
+	m, took, err := think(l.hint.agent, g)
+	if err != nil {
+		return gogame.Move{}, "", err
+	}
+	return m, fmt.Sprintf("Hint for %s: %s (%s, %s).", g.ToPlay, notation.FormatMove(g, m), l.hint.spec, formatThinking(took)), nil
+}
+
+// undo takes back the last move and, when that hands the turn to an agent,
+// the agent's move before it, so that undo returns to the human's turn.
+func (l *lineup) undo(g *gogame.Game) error {
+	if err := g.Undo(); err != nil {
+		return err
+	}
+	if l.player(g.ToPlay).agent != nil && g.MoveNumber() > 0 {
+		if err := g.Undo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatThinking(d time.Duration) string {
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"boardgame/gogame"
+	"boardgame/tsumego"
+)
+
+// puzzleStats is the drill history kept between sessions, keyed by puzzle file name.
+type puzzleStats struct {
+	Puzzles map[string]*puzzleRecord `json:"puzzles"`
+}
+
+type puzzleRecord struct {
+	Attempts   int       `json:"attempts"`
+	Solved     int       `json:"solved"`
+	LastResult string    `json:"last_result"`
+	LastPlayed time.Time `json:"last_played"`
+}
+
+func loadPuzzleStats(path string) (*puzzleStats, error) {
+	stats := &puzzleStats{Puzzles: map[string]*puzzleRecord{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if stats.Puzzles == nil {
+		stats.Puzzles = map[string]*puzzleRecord{}
+	}
+	return stats, nil
+}
+
+func (s *puzzleStats) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (s *puzzleStats) record(name string, verdict tsumego.Verdict) {
+	rec := s.Puzzles[name]
+	if rec == nil {
+		rec = &puzzleRecord{}
+		s.Puzzles[name] = rec
+	}
+	rec.Attempts++
+	if verdict == tsumego.Correct {
+		rec.Solved++
+	}
+	rec.LastResult = verdict.String()
+	rec.LastPlayed = time.Now()
+}
+
+// loadPuzzles reads every .sgf file in dir, in name order.
+func loadPuzzles(dir string) ([]*tsumego.Puzzle, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sgf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var puzzles []*tsumego.Puzzle
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p, err := tsumego.LoadPuzzle(filepath.Base(path), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		puzzles = append(puzzles, p)
+	}
+	if len(puzzles) == 0 {
+		return nil, fmt.Errorf("no .sgf problems in %s", dir)
+	}
+	return puzzles, nil
+}
+
+// goPuzzles runs "go puzzles DIR", keeping statistics between sessions.
+func goPuzzles(args []string) error {
+	flags := newFlags("go puzzles", "DIR")
+	statsPath := flags.String("stats", "", "puzzle statistics file (defaults to stats.json in DIR)")
+	parseFlags(flags, args, 1)
+	dir := flags.Arg(0)
+	if *statsPath == "" {
+		*statsPath = filepath.Join(dir, "stats.json")
+	}
+	return runPuzzles(dir, *statsPath, os.Stdin)
+}
+
+// runPuzzles drills the problems in dir, answering from each problem's
+// solution tree, and records results in the stats file.
+func runPuzzles(dir, statsPath string, in io.Reader) error {
+	puzzles, err := loadPuzzles(dir)
+	if err != nil {
+		return err
+	}
+	stats, err := loadPuzzleStats(statsPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Loaded %d problems from %s.\n", len(puzzles), dir)
+	fmt.Println("Commands: coordinate to play, 'retry' to restart the problem, 'skip' for the next one, 'quit' to exit.")
+	reader := bufio.NewReader(in)
+	solved, tried := 0, 0
+	for i := 0; i < len(puzzles); {
+		p := puzzles[i]
+		fmt.Printf("\nProblem %d/%d: %s", i+1, len(puzzles), p.Name)
+		if rec := stats.Puzzles[p.Name]; rec != nil {
+			fmt.Printf(" (solved %d of %d tries)", rec.Solved, rec.Attempts)
+		}
+		fmt.Println()
+		if c := p.Comment(); c != "" {
+			fmt.Println(c)
+		}
+		attempt := p.Start()
+		switch drillPuzzle(reader, attempt) {
+		case "quit":
+			printPuzzleSummary(stats, statsPath, solved, tried)
+			return nil
+		case "retry":
+			continue
+		case "skip":
+			fmt.Println("Skipped.")
+			i++
+			continue
+		}
+
+		tried++
+		if attempt.Verdict == tsumego.Correct {
+			solved++
+			fmt.Println("Correct!")
+		} else {
+			fmt.Println("Wrong.")
+		}
+		stats.record(p.Name, attempt.Verdict)
+		if err := stats.save(statsPath); err != nil {
+			return err
+		}
+		i++
+	}
+	printPuzzleSummary(stats, statsPath, solved, tried)
+	return nil
+}
+
+// drillPuzzle reads moves until the attempt is judged or the user asks to
+// quit, retry, or skip, returning that command ("" once judged).
+func drillPuzzle(reader *bufio.Reader, attempt *tsumego.Attempt) string {
+	fmt.Println(gogame.RenderBoardASCII(attempt.Game))
+	for attempt.Verdict == tsumego.Pending {
+		fmt.Printf("%s to play: ", attempt.Game.ToPlay)
+		raw, readErr := reader.ReadString('\n')
+		if readErr != nil && raw == "" {
+			fmt.Println()
+			return "quit"
+		}
+		input := strings.TrimSpace(strings.ToLower(raw))
+		switch input {
+		case "q", "quit", "exit":
+			return "quit"
+		case "retry", "skip":
+			return input
+		}
+		pos, err := gogame.ParseCoordRect(input, attempt.Game.Rows, attempt.Game.Cols)
+		if err != nil {
+			fmt.Printf("Invalid move: %v\n", err)
+			continue
+		}
+		reply, err := attempt.Play(pos)
+		if err != nil {
+			fmt.Printf("Invalid move: %v\n", err)
+			continue
+		}
+		if reply != nil {
+			fmt.Printf("%s answers %s.\n", reply.Color, notation.FormatMove(attempt.Game, *reply))
+		}
+		fmt.Println(gogame.RenderBoardASCII(attempt.Game))
+		if attempt.Comment != "" {
+			fmt.Println(attempt.Comment)
+		}
+	}
+	return ""
+}
+
+func printPuzzleSummary(stats *puzzleStats, statsPath string, solved, tried int) {
+	attempts, total := 0, 0
+	for _, rec := range stats.Puzzles {
+		attempts += rec.Attempts
+		total += rec.Solved
+	}
+	fmt.Printf("This session: %d of %d solved. All time: %d of %d attempts solved (stats in %s).\n", solved, tried, total, attempts, statsPath)
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+const replayHelp = "Commands: enter or 'n' next move, 'p' previous, 'j N' jump to move N, 'f' first, 'l' last, 'export ascii|svg|png [FILE]', 'q' quit."
+
+// replayer rebuilds the positions of a recorded game for the viewer.
+// Position 0 is the start of the game and position n follows move n.
+type replayer interface {
+	// moves returns the number of moves in the record.
+	moves() int
+	// show describes position n: the move that led to it, the board,
+	// captures, and any comment.
+	show(n int) (string, error)
+	// export renders position n as "ascii", "svg", or "png".
+	export(n int, format string) ([]byte, error)
+}
+
+// replay runs "replay FILE": a viewer that steps back and forth through an
+// SGF record or an engine game's JSON log.
+func replay(args []string) error {
+	flags := newFlags("replay", "FILE")
+	at := flags.Int("at", 0, "start at the position after this many moves (negative counts back from the end)")
+	format := flags.String("export", "", "print the -at position as ascii, svg, or png and exit instead of browsing")
+	out := flags.String("o", "", "write the -export output to this file instead of standard output")
+	theme := flags.String("theme", "classic", "diagram theme for svg and png: classic, print, or dark")
+	cell := flags.Int("cell", 30, "pixels between lines in svg and png diagrams")
+	numbers := flags.Int("numbers", 0, "number the stones in diagrams from this move on (0 for none)")
+	parseFlags(flags, args, 1)
+
+	opts := gogame.RenderOptions{CellSize: *cell, NumberFrom: *numbers}
+	var err error
+	if opts.Theme, err = gogame.ParseTheme(*theme); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	r, err := loadReplay(data, opts)
+	if err != nil {
+		return err
+	}
+	n := *at
+	if n < 0 {
+		n += r.moves() + 1
+	}
+	if n < 0 || n > r.moves() {
+		return fmt.Errorf("-at %d is outside the record's %d moves", *at, r.moves())
+	}
+	if *format != "" {
+		return exportPosition(r, n, *format, *out)
+	}
+	return browse(r, n, os.Stdin)
+}
+
+// loadReplay reads an engine JSON log, recognised by its opening brace, or
+// else an SGF record drawn with opts. The whole record is replayed once so
+// that a bad move is reported up front rather than when the viewer reaches
+// it.
+func loadReplay(data []byte, opts gogame.RenderOptions) (replayer, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		rec, err := engine.ParseRecord(data)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := rec.Replay(len(rec.Moves)); err != nil {
+			return nil, err
+		}
+		return &engineReplay{rec: rec}, nil
+	}
+	root, err := gogame.ParseSGF(string(data))
+	if err != nil {
+		return nil, err
+	}
+	rows, cols, err := gogame.SGFSize(root)
+	if err != nil {
+		return nil, err
+	}
+	r := &goReplay{root: root, opts: opts}
+	for i, node := range root.MainLine() {
+		_, ok, err := node.Move(rows, cols)
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		if ok {
+			r.nodes = append(r.nodes, node)
+		}
+	}
+	if _, _, err := r.position(len(r.nodes)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// browse runs the interactive viewer from position n until the input ends
+// or the user quits.
+func browse(r replayer, n int, in io.Reader) error {
+	fmt.Println(replayHelp)
+	reader := bufio.NewReader(in)
+	for {
+		text, err := r.show(n)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%sPosition %d of %d> ", text, n, r.moves())
+		raw, readErr := reader.ReadString('\n')
+		if readErr != nil && raw == "" {
+			fmt.Println()
+			return nil
+		}
+		fields := strings.Fields(raw)
+		if len(fields) == 0 {
+			fields = []string{"n"}
+		}
+		switch cmd := strings.ToLower(fields[0]); cmd {
+		case "n", "next":
+			if n == r.moves() {
+				fmt.Println("That was the last move.")
+			}
+			n = min(n+1, r.moves())
+		case "p", "prev":
+			if n == 0 {
+				fmt.Println("This is the start of the game.")
+			}
+			n = max(n-1, 0)
+		case "f", "first":
+			n = 0
+		case "l", "last":
+			n = r.moves()
+		case "j", "jump":
+			if len(fields) != 2 {
+				fmt.Println("Usage: j N")
+				continue
+			}
+			n = jumpTarget(fields[1], n, r.moves())
+		case "export":
+			if len(fields) < 2 || len(fields) > 3 {
+				fmt.Println("Usage: export ascii|svg|png [FILE]")
+				continue
+			}
+			file := ""
+			if len(fields) == 3 {
+				file = fields[2]
+			} else if strings.EqualFold(fields[1], "png") {
+				fmt.Println("PNG export needs a file: export png FILE")
+				continue
+			}
+			if err := exportPosition(r, n, fields[1], file); err != nil {
+				fmt.Printf("Cannot export: %v\n", err)
+			} else if file != "" {
+				fmt.Printf("Wrote position %d to %s.\n", n, file)
+			}
+		case "q", "quit":
+			return nil
+		case "h", "help":
+			fmt.Println(replayHelp)
+		default:
+			if _, err := strconv.Atoi(cmd); err == nil {
+				n = jumpTarget(cmd, n, r.moves())
+				continue
+			}
+			fmt.Printf("Unknown command %q. %s\n", cmd, replayHelp)
+		}
+	}
+}
+
+// jumpTarget parses the move number to jump to, clamped to the record. It
+// keeps the current position if text is not a number.
+func jumpTarget(text string, current, last int) int {
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		fmt.Printf("Invalid move number %q.\n", text)
+		return current
+	}
+	return min(max(n, 0), last)
+}
+
+// exportPosition writes position n in format to file, or prints it if file
+// is empty.
+func exportPosition(r replayer, n int, format, file string) error {
+	data, err := r.export(n, strings.ToLower(format))
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// goReplay replays the main line of an SGF record.
+type goReplay struct {
+	root  *gogame.SGFNode
+	nodes []*gogame.SGFNode // the main line's nodes that hold a move
+	opts  gogame.RenderOptions
+}
+
+func (r *goReplay) moves() int { return len(r.nodes) }
+
+// position returns the game after n moves and the result of the last one.
+func (r *goReplay) position(n int) (*gogame.Game, gogame.MoveResult, error) {
+	g, err := setupFromSGF(r.root)
+	if err != nil {
+		return nil, gogame.MoveResult{}, err
+	}
+	var res gogame.MoveResult
+	for i, node := range r.nodes[:n] {
+		m, _, _ := node.Move(g.Rows, g.Cols)
+		if res, err = g.Play(m); err != nil {
+			return nil, gogame.MoveResult{}, fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	return g, res, nil
+}
+
+func (r *goReplay) show(n int) (string, error) {
+	g, res, err := r.position(n)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	node := r.root
+	if n == 0 {
+		fmt.Fprintf(&sb, "Start of a %dx%d game", g.Cols, g.Rows)
+		if pb, pw := r.root.Get("PB"), r.root.Get("PW"); pb != "" || pw != "" {
+			fmt.Fprintf(&sb, ": %s (Black) vs %s (White)", orUnknown(pb), orUnknown(pw))
+		}
+	} else {
+		node = r.nodes[n-1]
+		rec, _ := g.LastMove()
+		fmt.Fprintf(&sb, "Move %d: %s %s", n, rec.Color, notation.FormatMove(g, rec.Move))
+		if res.Captured > 0 {
+			fmt.Fprintf(&sb, ", capturing %d", res.Captured)
+		}
+	}
+	sb.WriteString(".\n")
+	sb.WriteString(gogame.RenderBoardASCII(g))
+	fmt.Fprintf(&sb, "\nCaptures - Black: %d, White: %d.\n", g.Captures[gogame.Black], g.Captures[gogame.White])
+	// A record whose root holds the first move shows its comment there.
+	if n > 0 || r.root.Get("B")+r.root.Get("W") == "" {
+		if c := node.Get("C"); c != "" {
+			fmt.Fprintf(&sb, "Comment: %s\n", c)
+		}
+	}
+	if re := r.root.Get("RE"); re != "" && n == r.moves() {
+		fmt.Fprintf(&sb, "Result: %s.\n", re)
+	}
+	return sb.String(), nil
+}
+
+func (r *goReplay) export(n int, format string) ([]byte, error) {
+	g, _, err := r.position(n)
+	if err != nil {
+		return nil, err
+	}
+	opts := r.opts
+	node := r.root
+	if n > 0 {
+		node = r.nodes[n-1]
+	}
+	if opts.Markup, err = gogame.ParseMarkup(node, g.Rows, g.Cols); err != nil {
+		return nil, err
+	}
+	switch format {
+	case "ascii":
+		return []byte(gogame.RenderBoardASCII(g) + "\n"), nil
+	case "svg":
+		return []byte(gogame.RenderSVG(g, opts)), nil
+	case "png":
+		var buf bytes.Buffer
+		err := gogame.RenderPNG(&buf, g, opts)
+		return buf.Bytes(), err
+	default:
+		return nil, fmt.Errorf("unknown format %q (want ascii, svg, or png)", format)
+	}
+}
+
+// setupFromSGF starts a game with the record's size, setup stones, and side
+// to move, but none of its moves.
+func setupFromSGF(root *gogame.SGFNode) (*gogame.Game, error) {
+	rows, cols, err := gogame.SGFSize(root)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gogame.NewRectGame(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	setup := &gogame.SGFNode{Props: map[string][]string{}}
+	for _, name := range []string{"AB", "AW", "AE", "PL"} {
+		if v, ok := root.Props[name]; ok {
+			setup.Props[name] = v
+		}
+	}
+	if err := g.LoadSGF(setup); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func orUnknown(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// engineReplay replays a JSON log of one of the registered games.
+type engineReplay struct {
+	rec engine.Record
+}
+
+func (r *engineReplay) moves() int { return len(r.rec.Moves) }
+
+func (r *engineReplay) show(n int) (string, error) {
+	def, g, err := r.rec.Replay(n)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if n == 0 {
+		fmt.Fprintf(&sb, "Start of a game of %s.\n", def.Title)
+	} else {
+		last := g.Log[len(g.Log)-1]
+		name := fmt.Sprintf("Player %d", last.PlayerID)
+		for _, p := range g.Players {
+			if p.ID == last.PlayerID {
+				name = p.Name
+			}
+		}
+		fmt.Fprintf(&sb, "Move %d: %s plays %s.\n", n, name, r.rec.Moves[n-1])
+	}
+	sb.WriteString(def.Render(g))
+	sb.WriteByte('\n')
+	if outcome, done := def.Rule.Status(g); done {
+		if outcome.Winner != nil {
+			fmt.Fprintf(&sb, "%s wins.\n", outcome.Winner.Name)
+		} else {
+			sb.WriteString("Draw.\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+func (r *engineReplay) export(n int, format string) ([]byte, error) {
+	def, g, err := r.rec.Replay(n)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "ascii":
+		return []byte(def.Render(g) + "\n"), nil
+	case "svg", "png":
+		return nil, errors.New("diagrams are only available for Go records")
+	default:
+		return nil, fmt.Errorf("unknown format %q (want ascii, svg, or png)", format)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"boardgame/gogame"
+	"boardgame/storage"
+)
+
+// defaultStoreDir is where games are saved unless -store says otherwise.
+func defaultStoreDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "boardgame", "games")
+}
+
+// saver keeps the current game in a store under one ID, saving it whenever
+// it changes. A nil store disables saving.
+type saver struct {
+	store storage.Store
+	id    string
+	saved string // stateKey when last saved, so unchanged games are not rewritten
+}
+
+func newSaver(dir string) *saver {
+	s := &saver{}
+	if dir == "" {
+		return s
+	}
+	store, err := storage.NewFileStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Games will not be saved: %v\n", err)
+		return s
+	}
+	s.store = store
+	return s
+}
+
+func stateKey(g *gogame.Game) string {
+	return fmt.Sprintf("%d %s %s", g.MoveNumber(), g.Hash(), g.Result)
+}
+
+// autosave saves g if it changed since the last save. Empty new games are
+// not saved until the first move.
+func (s *saver) autosave(g *gogame.Game) {
+	if s.store == nil || stateKey(g) == s.saved {
+		return
+	}
+	if err := s.save(g); err != nil {
+		fmt.Printf("Could not save the game: %v\n", err)
+	}
+}
+
+func (s *saver) save(g *gogame.Game) error {
+	if s.store == nil {
+		return fmt.Errorf("saving is disabled (see -store)")
+	}
+	if err := s.store.Save(s.id, g); err != nil {
+		return err
+	}
+	s.saved = stateKey(g)
+	return nil
+}
+
+// load resumes the game stored under id and continues saving under it.
+func (s *saver) load(id string) (*gogame.Game, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("saving is disabled (see -store)")
+	}
+	g, err := s.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	s.id, s.saved = id, stateKey(g)
+	return g, nil
+}
+
+// start begins saving a new game under a fresh ID.
+func (s *saver) start(g *gogame.Game) {
+	s.id, s.saved = storage.NewID(), stateKey(g)
+}
+
+func (s *saver) printList() {
+	if s.store == nil {
+		fmt.Println("Saving is disabled (see -store).")
+		return
+	}
+	games, err := s.store.List()
+	if err != nil {
+		fmt.Printf("Cannot list games: %v\n", err)
+		return
+	}
+	if len(games) == 0 {
+		fmt.Println("No saved games.")
+		return
+	}
+	for _, sg := range games {
+		status := sg.ToPlay + " to play"
+		if sg.Result != "" {
+			status = sg.Result
+		}
+		fmt.Printf("%s  %dx%d %s, %d moves, %s (updated %s)\n", sg.ID, sg.Cols, sg.Rows, sg.Variant, sg.Moves, status, sg.Updated.Local().Format("2006-01-02 15:04"))
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"boardgame/server"
+	"boardgame/storage"
+	"boardgame/web"
+)
+
+// serve runs "serve": the HTTP API and web client, as cmd/server does.
+func serve(args []string) error {
+	flags := newFlags("serve", "")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	webDir := flags.String("web", "", "serve the web client from this directory instead of the built-in copy")
+	storeDir := flags.String("store", "", "save games in this directory so they survive restarts")
+	origins := flags.String("origins", "", "comma-separated origins of other sites whose pages may join rooms, e.g. https://example.com")
+	parseFlags(flags, args, 0)
+
+	var ui fs.FS = web.FS
+	if *webDir != "" {
+		ui = os.DirFS(*webDir)
+	}
+	var store storage.Store
+	if *storeDir != "" {
+		fileStore, err := storage.NewFileStore(*storeDir)
+		if err != nil {
+			return err
+		}
+		store = fileStore
+	}
+	srv := server.New(ui, store)
+	if *origins != "" {
+		srv.AllowedOrigins = strings.Split(*origins, ",")
+	}
+	fmt.Printf("Serving Go games on %s\n", *addr)
+	return http.ListenAndServe(*addr, srv)
+}
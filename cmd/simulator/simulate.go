@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"boardgame/engine"
+)
+
+// simulate runs "simulate": many games of one registered game between
+// random agents through engine.Play, reporting how often each player wins.
+func simulate(args []string) error {
+	flags := newFlags("simulate", "")
+	name := flags.String("game", "ttt", "game to simulate: "+gameNames())
+	n := flags.Int("n", 1000, "number of games to play")
+	seed := flags.Int64("seed", 0, "random seed (0 picks one from the clock)")
+	verbose := flags.Bool("v", false, "print the final board of every game")
+	parseFlags(flags, args, 0)
+
+	def, err := lookupGame(*name)
+	if err != nil {
+		return err
+	}
+	if *n <= 0 {
+		return fmt.Errorf("-n must be positive")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	r := rand.New(rand.NewSource(*seed)) //nolint:gosec // simulations, not security
+	agents := map[int]engine.Agent{}
+	for _, p := range def.Players {
+		agents[p.ID] = &engine.RandomAgent{Rand: r}
+	}
+
+	wins := map[int]int{}
+	draws, moves := 0, 0
+	for i := 0; i < *n; i++ {
+		g, err := def.NewGame(def.Players)
+		if err != nil {
+			return err
+		}
+		outcome, err := engine.Play(g, def.Rule, agents)
+		if err != nil {
+			return fmt.Errorf("game %d: %w", i+1, err)
+		}
+		moves += len(g.Log)
+		if outcome.Winner != nil {
+			wins[outcome.Winner.ID]++
+		} else {
+			draws++
+		}
+		if *verbose {
+			record := make([]string, len(g.Log))
+			for j, m := range g.Log {
+				record[j] = def.Notation.FormatMove(g, m)
+			}
+			fmt.Printf("Game %d: %s\n%s\n", i+1, strings.Join(record, " "), def.Render(g))
+			printOutcome(outcome)
+			fmt.Println()
+		}
+	}
+
+	fmt.Printf("Played %d games of %s between random agents (seed %d).\n", *n, def.Title, *seed)
+	for _, p := range def.Players {
+		fmt.Printf("%-6s %6d wins  %5.1f%%\n", p.Name, wins[p.ID], percent(wins[p.ID], *n))
+	}
+	fmt.Printf("%-6s %6d       %5.1f%%\n", "Draws", draws, percent(draws, *n))
+	fmt.Printf("Average length: %.1f moves.\n", float64(moves)/float64(*n))
+	return nil
+}
+
+func percent(part, total int) float64 {
+	return 100 * float64(part) / float64(total)
+}
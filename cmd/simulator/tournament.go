@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"boardgame/gogame"
+	"boardgame/gtp"
+)
+
+// standing is one tournament entrant and its record.
+type standing struct {
+	name         string
+	player       contestant
+	wins, losses int
+}
+
+// tournament runs "tournament": every pair of Go agents plays the same
+// number of games with each color, and the standings are printed at the end.
+func tournament(args []string) error {
+	flags := newFlags("tournament", "PLAYER...")
+	size := flags.Int("size", 9, "board size")
+	komi := flags.Float64("komi", 6.5, "points added to White's score")
+	rounds := flags.Int("rounds", 1, "games each pair plays with each color")
+	maxMoves := flags.Int("moves", 0, "moves after which a game is scored as it stands (defaults to three per point)")
+	parseFlags(flags, args, -1)
+	if flags.NArg() < 2 {
+		return errors.New("a tournament needs at least two players")
+	}
+	if *maxMoves <= 0 {
+		*maxMoves = *size * *size * 3
+	}
+
+	var entrants []*standing
+	defer func() {
+		for _, e := range entrants {
+			e.player.close()
+		}
+	}()
+	seen := map[string]int{}
+	for _, spec := range flags.Args() {
+		c, err := newContestant(spec, *komi)
+		if err != nil {
+			return err
+		}
+		entrants = append(entrants, &standing{name: spec, player: c})
+		if c.agent == nil {
+			return fmt.Errorf("tournament players must be agents, not %q", spec)
+		}
+		// Tell apart entrants with the same spec, e.g. two random players.
+		if seen[spec]++; seen[spec] > 1 {
+			entrants[len(entrants)-1].name = fmt.Sprintf("%s#%d", spec, seen[spec])
+		}
+	}
+
+	game := 0
+	for round := 0; round < *rounds; round++ {
+		for i, a := range entrants {
+			for j, b := range entrants {
+				if i == j {
+					continue
+				}
+				game++
+				result, winner, err := playMatch(a.player.agent, b.player.agent, *size, *komi, *maxMoves)
+				if err != nil {
+					return fmt.Errorf("game %d, %s vs %s: %w", game, a.name, b.name, err)
+				}
+				fmt.Printf("Game %d: %s (B) vs %s (W): %s\n", game, a.name, b.name, result)
+				switch winner {
+				case gogame.Black:
+					a.wins++
+					b.losses++
+				case gogame.White:
+					b.wins++
+					a.losses++
+				}
+			}
+		}
+	}
+	printStandings(entrants)
+	return nil
+}
+
+// playMatch plays one game between two agents and returns its SGF-style
+// result and winner. Games still going after maxMoves are scored as they are.
+func playMatch(black, white gogame.Agent, size int, komi float64, maxMoves int) (string, gogame.Color, error) {
+	g, err := gogame.NewGame(size)
+	if err != nil {
+		return "", gogame.None, err
+	}
+	for moves := 0; moves < maxMoves; moves++ {
+		if _, over := g.Status(); over {
+			break
+		}
+		agent := black
+		if g.ToPlay == gogame.White {
+			agent = white
+		}
+		m, err := agent.GenMove(g.Clone())
+		if errors.Is(err, gtp.ErrResign) {
+			_ = g.Resign(g.ToPlay)
+			break
+		}
+		if err != nil {
+			return "", gogame.None, err
+		}
+		m.Color = g.ToPlay
+		if _, err := g.Play(m); err != nil {
+			return "", gogame.None, fmt.Errorf("%s played an illegal move: %w", g.ToPlay, err)
+		}
+	}
+	if g.Result != "" {
+		return g.Result, g.Winner, nil
+	}
+	score := gogame.ScoreArea(g, komi)
+	return score.Result(), score.Winner(), nil
+}
+
+func printStandings(entrants []*standing) {
+	sorted := append([]*standing(nil), entrants...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].wins > sorted[j].wins })
+	width := len("Player")
+	for _, e := range sorted {
+		width = max(width, len(e.name))
+	}
+	fmt.Printf("\n%-*s  %4s  %6s  %6s\n", width, "Player", "Wins", "Losses", "Win%")
+	fmt.Println(strings.Repeat("-", width+24))
+	for _, e := range sorted {
+		fmt.Printf("%-*s  %4d  %6d  %5.1f%%\n", width, e.name, e.wins, e.losses, percent(e.wins, e.wins+e.losses))
+	}
+}
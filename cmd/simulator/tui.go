@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// ANSI escape sequences used by the full-screen interface.
+const (
+	ansiReset      = "\x1b[0m"
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiAltScreen  = "\x1b[?1049h"
+	ansiMainScreen = "\x1b[?1049l"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiBoard      = "\x1b[43;30m" // black on yellow, the wooden board
+	ansiBlackStone = "\x1b[43;30;1m"
+	ansiWhiteStone = "\x1b[43;97;1m"
+	ansiCursor     = "\x1b[7m"
+	ansiBold       = "\x1b[1m"
+	ansiDim        = "\x1b[2m"
+)
+
+// Keys that are not plain characters, returned by readKey.
+const (
+	keyUp rune = iota + 0x10000
+	keyDown
+	keyLeft
+	keyRight
+	keyEscape
+	keyCtrlC = 3
+)
+
+const tuiHelp = "arrows/hjkl move  enter play  p pass  u undo  ? hint  s score  e estimate  w save  r resign  q quit"
+
+// tui is the full-screen interface: a cursor over a colored board with the
+// move list beside it, driven by single keys.
+type tui struct {
+	game          *gogame.Game
+	saves         *saver
+	players       *lineup
+	komi          float64
+	cursor        engine.Position
+	status        string
+	confirmResign bool
+	out           *bufio.Writer
+}
+
+// useTUI reports whether the full-screen interface can run: both standard
+// input and output must be terminals.
+func useTUI() bool {
+	return isTerminal(int(os.Stdin.Fd())) && isTerminal(int(os.Stdout.Fd()))
+}
+
+// playTUI runs game in the full-screen interface until the user quits,
+// saving it after every change like the line-mode loop.
+func playTUI(game *gogame.Game, saves *saver, players *lineup, komi float64) error {
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	t := &tui{
+		game:    game,
+		saves:   saves,
+		players: players,
+		komi:    komi,
+		cursor:  engine.Position{Row: game.Rows / 2, Col: game.Cols / 2},
+		status:  "Welcome. Move the cursor and press enter to play.",
+		out:     bufio.NewWriter(os.Stdout),
+	}
+	if saves.store != nil {
+		t.status += fmt.Sprintf(" This game is saved automatically as %s.", saves.id)
+	}
+	t.out.WriteString(ansiAltScreen + ansiHideCursor)
+	defer func() {
+		t.out.WriteString(ansiShowCursor + ansiMainScreen)
+		t.out.Flush()
+		restore()
+		saves.autosave(t.game)
+		printBoard(t.game)
+		if t.game.Result != "" {
+			printResult(t.game)
+		}
+	}()
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		saves.autosave(t.game)
+		if p, ok := players.agentToMove(t.game); ok {
+			t.status = fmt.Sprintf("%s (%s) is thinking...", t.game.ToPlay, p.spec)
+			t.draw()
+			msg, err := agentMove(t.game, p)
+			if err != nil {
+				t.status = err.Error()
+				t.draw()
+				return nil
+			}
+			t.status = msg
+			if t.game.Result != "" {
+				t.status += " " + t.result()
+			} else if t.game.ConsecutivePasses >= 2 {
+				t.status += " Both players passed. " + t.score()
+			}
+			continue
+		}
+		t.draw()
+		key, err := readKey(in)
+		if err != nil || !t.handle(key) {
+			return nil
+		}
+	}
+}
+
+// readKey reads one key press, decoding the escape sequences arrow keys send.
+func readKey(in *bufio.Reader) (rune, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != 0x1b {
+		return rune(b), nil
+	}
+	// A lone escape arrives by itself; sequences arrive in one read.
+	if in.Buffered() == 0 {
+		return keyEscape, nil
+	}
+	if next, _ := in.ReadByte(); next != '[' && next != 'O' {
+		return keyEscape, nil
+	}
+	code, _ := in.ReadByte()
+	switch code {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'C':
+		return keyRight, nil
+	case 'D':
+		return keyLeft, nil
+	}
+	return keyEscape, nil
+}
+
+// handle applies one key and reports whether to keep running.
+func (t *tui) handle(key rune) bool {
+	confirming := t.confirmResign
+	t.confirmResign = false
+	if err := t.game.CheckTime(); err != nil {
+		t.status = fmt.Sprintf("%v. %s", err, t.result())
+	}
+	switch key {
+	case 'q', 'Q', keyCtrlC:
+		return false
+	case keyUp, 'k':
+		t.cursor.Row = max(t.cursor.Row-1, 0)
+	case keyDown, 'j':
+		t.cursor.Row = min(t.cursor.Row+1, t.game.Rows-1)
+	case keyLeft, 'h':
+		t.cursor.Col = max(t.cursor.Col-1, 0)
+	case keyRight, 'l':
+		t.cursor.Col = min(t.cursor.Col+1, t.game.Cols-1)
+	case '\r', '\n', ' ':
+		mover := t.game.ToPlay
+		res, err := t.game.PlayMove(t.cursor)
+		if err != nil {
+			t.status = fmt.Sprintf("Invalid move: %v.", err)
+			break
+		}
+		t.status = fmt.Sprintf("%s played %s.", mover, gogame.FormatCoord(t.cursor, t.game.Rows, t.game.Cols))
+		if res.Captured > 0 {
+			t.status += fmt.Sprintf(" Captured %d.", res.Captured)
+		}
+		if t.game.Result != "" {
+			t.status += " " + t.result()
+		}
+	case 'p', 'P':
+		if t.game.ToPlay == gogame.None {
+			t.status = "The game is finished."
+			break
+		}
+		mover := t.game.ToPlay
+		t.game.Pass()
+		t.status = fmt.Sprintf("%s passed.", mover)
+		if t.game.ConsecutivePasses >= 2 {
+			t.status += " Both players passed. " + t.score()
+		}
+	case 'u', 'U':
+		if err := t.players.undo(t.game); err != nil {
+			t.status = fmt.Sprintf("Cannot undo: %v.", err)
+			break
+		}
+		t.status = "Took back the last move."
+	case '?':
+		m, msg, err := t.players.hintFor(t.game)
+		if err != nil {
+			t.status = fmt.Sprintf("No hint: %v.", err)
+			break
+		}
+		if !m.Pass {
+			t.cursor = m.Pos
+		}
+		t.status = msg
+	case 's', 'S':
+		t.status = t.score()
+	case 'e', 'E':
+		estimate := gogame.EstimateOwnership(t.game, t.komi)
+		t.status = fmt.Sprintf("Estimated result (komi %.1f): %s.", t.komi, estimate.Result())
+	case 'w', 'W':
+		if err := t.saves.save(t.game); err != nil {
+			t.status = fmt.Sprintf("Cannot save: %v.", err)
+			break
+		}
+		t.status = fmt.Sprintf("Saved as %s; resume with -resume %s.", t.saves.id, t.saves.id)
+	case 'r', 'R':
+		if !confirming {
+			t.confirmResign = true
+			t.status = "Press r again to resign."
+			break
+		}
+		resigner := t.game.ToPlay
+		if err := t.game.Resign(resigner); err != nil {
+			t.status = fmt.Sprintf("Cannot resign: %v.", err)
+			break
+		}
+		t.status = fmt.Sprintf("%s resigned. %s", resigner, t.result())
+	}
+	return true
+}
+
+func (t *tui) score() string {
+	score := gogame.ScoreArea(t.game, t.komi)
+	return fmt.Sprintf("Score (area, komi %.1f) - Black: %.1f, White: %.1f. Result: %s.", t.komi, score.Black, score.White, score.Result())
+}
+
+func (t *tui) result() string {
+	return fmt.Sprintf("%s wins (%s).", t.game.Winner, t.game.Result)
+}
+
+// draw repaints the screen: the board on the left, the game panel on the
+// right, then the status and help lines.
+func (t *tui) draw() {
+	board := t.boardLines()
+	panel := t.panelLines(len(board))
+	t.out.WriteString(ansiClear)
+	for i, line := range board {
+		t.out.WriteString(line + "   " + panel[i] + "\r\n")
+	}
+	t.out.WriteString("\r\n" + t.status + "\r\n")
+	t.out.WriteString(ansiDim + tuiHelp + ansiReset + "\r\n")
+	t.out.Flush()
+}
+
+func (t *tui) boardLines() []string {
+	g := t.game
+	labels := gogame.ColumnLabels(g.Cols)
+	width := len(labels[len(labels)-1])
+	last := engine.Position{Row: -1, Col: -1}
+	if rec, ok := g.LastMove(); ok && !rec.Pass {
+		last = rec.Pos
+	}
+
+	var header strings.Builder
+	header.WriteString("   ")
+	for _, l := range labels {
+		header.WriteString(fmt.Sprintf("%-*s ", width, l))
+	}
+	header.WriteString("  ") // the width of the row numbers on the right
+	lines := []string{header.String()}
+	for row := 0; row < g.Rows; row++ {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%2d ", g.Rows-row))
+		for col := 0; col < g.Cols; col++ {
+			pos := engine.Position{Row: row, Col: col}
+			v, _ := g.Board.Get(pos)
+			color, ch := ansiBoard, "+"
+			switch gogame.Color(v) {
+			case gogame.Black:
+				color, ch = ansiBlackStone, "●"
+			case gogame.White:
+				color, ch = ansiWhiteStone, "●"
+			}
+			if pos == last {
+				ch = "◉"
+			}
+			cell := ch + strings.Repeat(" ", width-1)
+			if pos == t.cursor {
+				cell = ansiCursor + cell + ansiReset + color
+			}
+			sb.WriteString(color + cell)
+			if col < g.Cols-1 {
+				sb.WriteString(ansiBoard + " ")
+			}
+		}
+		sb.WriteString(ansiReset + fmt.Sprintf(" %2d", g.Rows-row))
+		lines = append(lines, sb.String())
+	}
+	return append(lines, header.String())
+}
+
+// panelLines returns n lines describing the game: whose turn it is,
+// captures, clocks, and as much of the move list as fits.
+func (t *tui) panelLines(n int) []string {
+	g := t.game
+	var lines []string
+	switch {
+	case g.Result != "":
+		lines = append(lines, ansiBold+"Game over: "+g.Result+ansiReset)
+	case g.ConsecutivePasses >= 2:
+		lines = append(lines, ansiBold+"Game over: both passed"+ansiReset)
+	default:
+		lines = append(lines, fmt.Sprintf(ansiBold+"Move %d: %s to play"+ansiReset, g.MoveNumber()+1, g.ToPlay))
+	}
+	lines = append(lines, fmt.Sprintf("Captures  Black %d  White %d", g.Captures[gogame.Black], g.Captures[gogame.White]))
+	if g.Clock != nil {
+		lines = append(lines, fmt.Sprintf("Clock  Black %s  White %s", formatClock(g.Clock.Remaining(gogame.Black)), formatClock(g.Clock.Remaining(gogame.White))))
+	}
+	lines = append(lines, "", "Moves")
+
+	moves := g.Moves()
+	room := n - len(lines)
+	start := max(len(moves)-room, 0)
+	for i := start; i < len(moves); i++ {
+		lines = append(lines, fmt.Sprintf("%4d. %s %s", i+1, moves[i].Color, notation.FormatMove(g, moves[i].Move)))
+	}
+	for len(lines) < n {
+		lines = append(lines, "")
+	}
+	return lines[:n]
+}
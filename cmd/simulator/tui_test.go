@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+func newTestTUI(t *testing.T, size int) *tui {
+	t.Helper()
+	g, err := gogame.NewGame(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tui{game: g}
+}
+
+func TestReadKeyDecodesArrowKeys(t *testing.T) {
+	// Terminals send arrows as ESC [ x or, in application mode, ESC O x.
+	in := bufio.NewReader(strings.NewReader("\x1b[A\x1bOBx\x1b[C\x1b[D\x1b[Z"))
+	for _, want := range []rune{keyUp, keyDown, 'x', keyRight, keyLeft, keyEscape} {
+		got, err := readKey(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("expected key %#x, got %#x", want, got)
+		}
+	}
+	if _, err := readKey(in); err == nil {
+		t.Fatal("expected an error at the end of input")
+	}
+
+	lone := bufio.NewReader(strings.NewReader("\x1b"))
+	if got, err := readKey(lone); err != nil || got != keyEscape {
+		t.Fatalf("expected a lone escape, got %#x (%v)", got, err)
+	}
+}
+
+func TestHandleClampsCursorToBoard(t *testing.T) {
+	tu := newTestTUI(t, 9)
+	for _, key := range []rune{keyUp, 'k', keyLeft, 'h'} {
+		tu.handle(key)
+	}
+	if tu.cursor != (engine.Position{}) {
+		t.Fatalf("expected the cursor to stay in the top left corner, got %+v", tu.cursor)
+	}
+	tu.cursor = engine.Position{Row: 8, Col: 8}
+	for _, key := range []rune{keyDown, 'j', keyRight, 'l'} {
+		tu.handle(key)
+	}
+	if tu.cursor != (engine.Position{Row: 8, Col: 8}) {
+		t.Fatalf("expected the cursor to stay in the bottom right corner, got %+v", tu.cursor)
+	}
+	tu.handle(keyUp)
+	tu.handle('h')
+	if tu.cursor != (engine.Position{Row: 7, Col: 7}) {
+		t.Fatalf("expected the cursor to move up and left, got %+v", tu.cursor)
+	}
+	if !tu.handle('x') || tu.handle('q') {
+		t.Fatal("expected only q to stop the interface")
+	}
+}
+
+func TestResignNeedsConfirmation(t *testing.T) {
+	tu := newTestTUI(t, 9)
+	tu.handle('r')
+	if tu.game.Result != "" || tu.status != "Press r again to resign." {
+		t.Fatalf("expected a confirmation prompt, got result %q, status %q", tu.game.Result, tu.status)
+	}
+	// Any other key cancels the confirmation.
+	tu.handle(keyDown)
+	tu.handle('r')
+	if tu.game.Result != "" {
+		t.Fatalf("expected the game to continue, got %q", tu.game.Result)
+	}
+	tu.handle('R')
+	if tu.game.Result != "W+R" || !strings.HasPrefix(tu.status, "Black resigned.") {
+		t.Fatalf("expected Black to resign, got result %q, status %q", tu.game.Result, tu.status)
+	}
+}
+
+func TestBoardAndPanelShowLastMoveAndCursor(t *testing.T) {
+	tu := newTestTUI(t, 9)
+	tu.cursor = engine.Position{Row: 4, Col: 4}
+	tu.handle('\r')
+	if tu.status != "Black played E5." {
+		t.Fatalf("unexpected status %q", tu.status)
+	}
+	tu.handle(keyUp)
+
+	lines := tu.boardLines()
+	if len(lines) != 9+2 {
+		t.Fatalf("expected a header, 9 rows, and a footer, got %d lines", len(lines))
+	}
+	// Line 0 is the header, so row r is line r+1.
+	if got := strings.Count(strings.Join(lines, "\n"), "◉"); got != 1 || !strings.Contains(lines[5], "◉") {
+		t.Fatalf("expected one last-move marker on E5's row, got %d:\n%s", got, lines[5])
+	}
+	if got := strings.Count(strings.Join(lines, "\n"), ansiCursor); got != 1 || !strings.Contains(lines[4], ansiCursor) {
+		t.Fatalf("expected the cursor on E6's row, got %d:\n%s", got, lines[4])
+	}
+
+	panel := tu.panelLines(len(lines))
+	if len(panel) != len(lines) {
+		t.Fatalf("expected %d panel lines, got %d", len(lines), len(panel))
+	}
+	if !strings.Contains(panel[0], "Move 2: White to play") {
+		t.Fatalf("unexpected panel heading %q", panel[0])
+	}
+	if !strings.Contains(strings.Join(panel, "\n"), "1. Black E5") {
+		t.Fatalf("expected the move list to show E5:\n%s", strings.Join(panel, "\n"))
+	}
+}
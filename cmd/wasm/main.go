@@ -0,0 +1,49 @@
+//go:build js && wasm
+
+// Command wasm runs the gogame rules in the browser. It exposes a global
+// gogame object whose functions wrap gameapi.Bridge:
+//
+//	gogame.newGame(optionsJSON)  start a game, returning {"state"} or {"error"}
+//	gogame.act(actionJSON)       play, pass, undo, or resign
+//	gogame.state()               the current state
+//	gogame.score(komi)           area score
+//	gogame.sgf()                 SGF record
+//
+// Build it next to the web client, which uses it for local games when present:
+//
+//	GOOS=js GOARCH=wasm go build -o web/gogame.wasm ./cmd/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" web/
+//	go run ./cmd/server -web web
+package main
+
+import (
+	"syscall/js"
+
+	"boardgame/gameapi"
+)
+
+func main() {
+	var b gameapi.Bridge
+	str := func(args []js.Value, i int) string {
+		if i < len(args) && args[i].Type() == js.TypeString {
+			return args[i].String()
+		}
+		return ""
+	}
+	api := map[string]any{
+		"newGame": js.FuncOf(func(_ js.Value, args []js.Value) any { return b.NewGame(str(args, 0)) }),
+		"act":     js.FuncOf(func(_ js.Value, args []js.Value) any { return b.Act(str(args, 0)) }),
+		"state":   js.FuncOf(func(_ js.Value, _ []js.Value) any { return b.State() }),
+		"score": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			komi := 6.5
+			if len(args) > 0 && args[0].Type() == js.TypeNumber {
+				komi = args[0].Float()
+			}
+			return b.Score(komi)
+		}),
+		"sgf": js.FuncOf(func(_ js.Value, _ []js.Value) any { return b.SGF() }),
+	}
+	js.Global().Set("gogame", js.ValueOf(api))
+	// Keep the exported functions alive for the life of the page.
+	select {}
+}
@@ -0,0 +1,406 @@
+// Package correspondence runs slow, asynchronous Go games: each move has a
+// deadline measured in days, players can take vacation to freeze their
+// deadline, and players who miss a deadline lose on time. A Manager keeps
+// games in a Store and reports turns, deadline warnings, and results
+// through a Notifier.
+package correspondence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"boardgame/gameapi"
+	"boardgame/gogame"
+	"boardgame/storage"
+)
+
+// Day is the unit of correspondence time settings.
+const Day = 24 * time.Hour
+
+// Settings are chosen when a game is created.
+type Settings struct {
+	Size         int     `json:"size"`         // board size; 0 means 19
+	Komi         float64 `json:"komi"`         // used to score games ended by two passes
+	MoveDays     int     `json:"moveDays"`     // days each player has for every move; 0 means 3
+	VacationDays int     `json:"vacationDays"` // vacation allowance per player for the whole game
+}
+
+// Player is one side of a game.
+type Player struct {
+	Name         string        `json:"name"`
+	VacationLeft time.Duration `json:"vacationLeft"`
+	OnVacation   bool          `json:"onVacation"`
+	// VacationSince is when vacation last started counting against
+	// VacationLeft; it only counts while it is the player's move.
+	VacationSince time.Time `json:"vacationSince,omitempty"`
+}
+
+// Game is a correspondence game and its timing state.
+type Game struct {
+	ID       string       `json:"id"`
+	Black    Player       `json:"black"`
+	White    Player       `json:"white"`
+	Settings Settings     `json:"settings"`
+	Created  time.Time    `json:"created"`
+	Updated  time.Time    `json:"updated"`
+	Game     *gogame.Game `json:"-"`
+	// Deadline is when the player to move loses on time, including any
+	// vacation they have already used this move.
+	Deadline time.Time `json:"deadline"`
+	// Warned records that the deadline warning for this move was sent.
+	Warned bool `json:"warned,omitempty"`
+	// Result is the final result, including scored results after two
+	// passes, which gogame leaves to the caller.
+	Result string `json:"result,omitempty"`
+	// Version counts the saves of the game. A Store refuses to save a copy
+	// whose Version is not the stored one, so a stale copy cannot overwrite
+	// a newer move.
+	Version int `json:"version"`
+}
+
+// Over reports whether the game has ended.
+func (cg *Game) Over() bool {
+	return cg.Result != ""
+}
+
+// Player returns the side playing color c.
+func (cg *Game) Player(c gogame.Color) *Player {
+	if c == gogame.White {
+		return &cg.White
+	}
+	return &cg.Black
+}
+
+// ColorOf returns the color name plays, or gogame.None for someone else.
+func (cg *Game) ColorOf(name string) gogame.Color {
+	switch {
+	case strings.EqualFold(name, cg.Black.Name):
+		return gogame.Black
+	case strings.EqualFold(name, cg.White.Name):
+		return gogame.White
+	default:
+		return gogame.None
+	}
+}
+
+// ToPlay returns the player to move, or nil once the game is over.
+func (cg *Game) ToPlay() *Player {
+	if cg.Over() {
+		return nil
+	}
+	return cg.Player(cg.Game.ToPlay)
+}
+
+// TimeLeft returns how long the player to move has before the deadline at
+// now, not counting vacation still to be used.
+func (cg *Game) TimeLeft(now time.Time) time.Duration {
+	return cg.Deadline.Sub(now)
+}
+
+// settle brings the timing state up to now: vacation taken by the player to
+// move pushes their deadline back until their allowance runs out, and a
+// missed deadline ends the game. It reports whether the game timed out.
+func (cg *Game) settle(now time.Time) bool {
+	if cg.Over() {
+		return false
+	}
+	mover := cg.Game.ToPlay
+	p := cg.Player(mover)
+	if p.OnVacation {
+		used := now.Sub(p.VacationSince)
+		if used >= p.VacationLeft {
+			used = p.VacationLeft
+			p.OnVacation = false
+		}
+		if used > 0 {
+			cg.Deadline = cg.Deadline.Add(used)
+			p.VacationLeft -= used
+		}
+		p.VacationSince = now
+	}
+	if now.After(cg.Deadline) {
+		_ = cg.Game.TimeOut(mover)
+		cg.Result = cg.Game.Result
+		return true
+	}
+	return false
+}
+
+// startTurn gives the player now to move a fresh deadline.
+func (cg *Game) startTurn(now time.Time) {
+	cg.Deadline = now.Add(time.Duration(cg.Settings.MoveDays) * Day)
+	cg.Warned = false
+	if p := cg.ToPlay(); p != nil && p.OnVacation {
+		p.VacationSince = now
+	}
+}
+
+// ErrNotYourGame is returned when someone other than the two players acts.
+var ErrNotYourGame = errors.New("you are not playing in this game")
+
+// ErrConflict is returned when a game is saved from a stale copy because
+// it changed after the copy was loaded.
+var ErrConflict = errors.New("game was changed by someone else")
+
+// Manager creates and advances correspondence games.
+type Manager struct {
+	Store    Store
+	Notifier Notifier // may be nil
+	// Now returns the current time; tests substitute a fake clock.
+	Now func() time.Time
+	// Warning is how long before a deadline the player to move is reminded;
+	// 0 means one day.
+	Warning time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // per game, held from load to save
+}
+
+// NewManager returns a manager using the real clock.
+func NewManager(store Store, notifier Notifier) *Manager {
+	return &Manager{Store: store, Notifier: notifier, Now: time.Now}
+}
+
+// Create starts a game between black and white and tells Black to move.
+func (m *Manager) Create(black, white string, s Settings) (*Game, error) {
+	if black == "" || white == "" || strings.EqualFold(black, white) {
+		return nil, fmt.Errorf("a game needs two different players")
+	}
+	if s.Size == 0 {
+		s.Size = 19
+	}
+	if s.MoveDays <= 0 {
+		s.MoveDays = 3
+	}
+	if s.VacationDays < 0 {
+		return nil, fmt.Errorf("vacation days cannot be negative")
+	}
+	g, err := gogame.NewGame(s.Size)
+	if err != nil {
+		return nil, err
+	}
+	now := m.Now()
+	vacation := time.Duration(s.VacationDays) * Day
+	cg := &Game{
+		ID:       storage.NewID(),
+		Black:    Player{Name: black, VacationLeft: vacation},
+		White:    Player{Name: white, VacationLeft: vacation},
+		Settings: s,
+		Created:  now,
+		Game:     g,
+	}
+	cg.startTurn(now)
+	if err := m.save(cg, now); err != nil {
+		return nil, err
+	}
+	m.notify(cg, Event{Kind: YourTurn, Player: black, Message: fmt.Sprintf("New game against %s: you play Black.", white)})
+	return cg, nil
+}
+
+// Load returns a game with its timing brought up to date, saving and
+// announcing a timeout if one has happened.
+func (m *Manager) Load(id string) (*Game, error) {
+	defer m.lock(id)()
+	return m.load(id)
+}
+
+// load is Load for callers that hold the game's lock.
+func (m *Manager) load(id string) (*Game, error) {
+	cg, err := m.Store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.update(cg, m.Now()); err != nil {
+		return nil, err
+	}
+	return cg, nil
+}
+
+// Play applies a player's action: "play", "pass", or "resign" (which is
+// allowed on the opponent's turn too). Take-backs are not offered in
+// correspondence games. It returns the updated game and a log line.
+func (m *Manager) Play(id, player string, a gameapi.Action) (*Game, string, error) {
+	defer m.lock(id)()
+	cg, err := m.load(id)
+	if err != nil {
+		return nil, "", err
+	}
+	color := cg.ColorOf(player)
+	if color == gogame.None {
+		return nil, "", ErrNotYourGame
+	}
+	if cg.Over() {
+		return cg, "", fmt.Errorf("game is over: %s", cg.Result)
+	}
+	now := m.Now()
+	var msg string
+	switch strings.ToLower(a.Type) {
+	case "resign":
+		if err := cg.Game.Resign(color); err != nil {
+			return cg, "", err
+		}
+		msg = fmt.Sprintf("%s resigned.", color)
+	case "undo":
+		return cg, "", fmt.Errorf("%w: take-backs are not allowed in correspondence games", gameapi.ErrBadAction)
+	default:
+		if cg.Game.ToPlay != color {
+			return cg, "", fmt.Errorf("it is not your turn")
+		}
+		if msg, err = gameapi.Apply(cg.Game, a); err != nil {
+			return cg, "", err
+		}
+	}
+
+	if _, over := cg.Game.Status(); over {
+		cg.Result = cg.Game.Result
+		if cg.Result == "" {
+			cg.Result = gogame.ScoreArea(cg.Game, cg.Settings.Komi).Result()
+		}
+	} else {
+		cg.startTurn(now)
+	}
+	if err := m.save(cg, now); err != nil {
+		return nil, "", err
+	}
+	if cg.Over() {
+		m.announceResult(cg, msg)
+	} else {
+		next := cg.ToPlay()
+		m.notify(cg, Event{Kind: YourTurn, Player: next.Name, Message: fmt.Sprintf("%s Your move, due %s.", msg, cg.Deadline.Format(time.RFC1123))})
+	}
+	return cg, msg, nil
+}
+
+// SetVacation starts or ends a player's vacation. While it is their move,
+// vacation freezes their deadline until the allowance runs out.
+func (m *Manager) SetVacation(id, player string, on bool) (*Game, error) {
+	defer m.lock(id)()
+	cg, err := m.load(id)
+	if err != nil {
+		return nil, err
+	}
+	color := cg.ColorOf(player)
+	if color == gogame.None {
+		return nil, ErrNotYourGame
+	}
+	if cg.Over() {
+		return cg, fmt.Errorf("game is over: %s", cg.Result)
+	}
+	p := cg.Player(color)
+	if on && p.VacationLeft <= 0 {
+		return cg, fmt.Errorf("no vacation time left")
+	}
+	now := m.Now()
+	p.OnVacation = on
+	p.VacationSince = now
+	return cg, m.save(cg, now)
+}
+
+// CheckDeadlines brings every unfinished game up to date, ending those whose
+// deadline has passed and warning players whose deadline is near. It
+// returns the games that timed out. A game that another process changes
+// while it is being checked is left for the next check.
+func (m *Manager) CheckDeadlines() ([]*Game, error) {
+	games, err := m.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	var timedOut []*Game
+	for _, listed := range games {
+		if listed.Over() {
+			continue
+		}
+		// Reload under the lock so that a move made since the listing is
+		// not overwritten.
+		unlock := m.lock(listed.ID)
+		cg, err := m.load(listed.ID)
+		unlock()
+		if errors.Is(err, ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return timedOut, err
+		}
+		if cg.Over() {
+			timedOut = append(timedOut, cg)
+		}
+	}
+	return timedOut, nil
+}
+
+// update settles cg at now and sends any timeout or warning it causes,
+// saving the game if anything changed.
+func (m *Manager) update(cg *Game, now time.Time) error {
+	if cg.Over() {
+		return nil
+	}
+	before := *cg
+	if cg.settle(now) {
+		if err := m.save(cg, now); err != nil {
+			return err
+		}
+		loser := cg.Player(gogame.Black)
+		if cg.Game.Winner == gogame.Black {
+			loser = cg.Player(gogame.White)
+		}
+		m.announceResult(cg, fmt.Sprintf("%s missed the deadline.", loser.Name))
+		return nil
+	}
+	p := cg.ToPlay()
+	if !cg.Warned && !p.OnVacation && cg.TimeLeft(now) <= m.warning() {
+		cg.Warned = true
+		m.notify(cg, Event{Kind: DeadlineWarning, Player: p.Name, Message: fmt.Sprintf("Your move is due %s.", cg.Deadline.Format(time.RFC1123))})
+	}
+	if cg.Deadline != before.Deadline || cg.Warned != before.Warned || cg.Black != before.Black || cg.White != before.White {
+		return m.save(cg, now)
+	}
+	return nil
+}
+
+func (m *Manager) warning() time.Duration {
+	if m.Warning <= 0 {
+		return Day
+	}
+	return m.Warning
+}
+
+// lock locks the game with the given ID and returns its unlock function.
+func (m *Manager) lock(id string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*sync.Mutex{}
+	}
+	l := m.locks[id]
+	if l == nil {
+		l = &sync.Mutex{}
+		m.locks[id] = l
+	}
+	m.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+func (m *Manager) save(cg *Game, now time.Time) error {
+	cg.Updated = now
+	return m.Store.Save(cg)
+}
+
+func (m *Manager) announceResult(cg *Game, msg string) {
+	for _, name := range []string{cg.Black.Name, cg.White.Name} {
+		m.notify(cg, Event{Kind: GameOver, Player: name, Message: fmt.Sprintf("%s Result: %s.", msg, cg.Result)})
+	}
+}
+
+// notify fills in the event's game and time and passes it on. Notification
+// failures do not undo moves, so they are dropped.
+func (m *Manager) notify(cg *Game, e Event) {
+	if m.Notifier == nil {
+		return
+	}
+	e.GameID = cg.ID
+	e.Time = m.Now()
+	_ = m.Notifier.Notify(e)
+}
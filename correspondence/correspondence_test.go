@@ -0,0 +1,193 @@
+package correspondence
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"boardgame/gameapi"
+)
+
+func TestDeadlinesAndVacation(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	m := &Manager{Store: store, Notifier: &LogNotifier{W: &log}, Now: func() time.Time { return now }}
+	advance := func(d time.Duration) { now = now.Add(d) }
+
+	cg, err := m.Create("alice", "bob", Settings{Size: 9, MoveDays: 3, VacationDays: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	advance(Day)
+	if _, _, err := m.Play(cg.ID, "bob", gameapi.Action{Type: "play", Coord: "E5"}); err == nil {
+		t.Fatal("expected White to be refused on Black's turn")
+	}
+	if _, _, err := m.Play(cg.ID, "carol", gameapi.Action{Type: "pass"}); err != ErrNotYourGame {
+		t.Fatalf("expected ErrNotYourGame, got %v", err)
+	}
+	cg, _, err = m.Play(cg.ID, "alice", gameapi.Action{Type: "play", Coord: "E5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(3 * Day); !cg.Deadline.Equal(want) {
+		t.Fatalf("expected Bob's deadline at %v, got %v", want, cg.Deadline)
+	}
+	if _, _, err := m.Play(cg.ID, "alice", gameapi.Action{Type: "undo"}); err == nil {
+		t.Fatal("expected take-backs to be refused")
+	}
+
+	// Bob's two vacation days push his deadline back, then run out.
+	advance(Day)
+	if _, err := m.SetVacation(cg.ID, "bob", true); err != nil {
+		t.Fatal(err)
+	}
+	advance(36 * time.Hour)
+	if out, err := m.CheckDeadlines(); err != nil || len(out) != 0 {
+		t.Fatalf("expected no timeouts during vacation, got %v (%v)", out, err)
+	}
+	if strings.Contains(log.String(), "deadline-warning") {
+		t.Fatal("expected no warning while on vacation")
+	}
+	advance(36 * time.Hour)
+	if _, err := m.CheckDeadlines(); err != nil {
+		t.Fatal(err)
+	}
+	cg, _ = m.Load(cg.ID)
+	if cg.White.OnVacation || cg.White.VacationLeft != 0 || cg.TimeLeft(now) != Day {
+		t.Fatalf("expected vacation to be used up with a day left, got %+v, %v left", cg.White, cg.TimeLeft(now))
+	}
+	if !strings.Contains(log.String(), "deadline-warning game="+cg.ID+" to=bob") {
+		t.Fatalf("expected a deadline warning for Bob, log:\n%s", log.String())
+	}
+
+	advance(Day + time.Hour)
+	out, err := m.CheckDeadlines()
+	if err != nil || len(out) != 1 || out[0].Result != "B+T" {
+		t.Fatalf("expected Bob to lose on time, got %v (%v)", out, err)
+	}
+	if _, _, err := m.Play(cg.ID, "bob", gameapi.Action{Type: "play", Coord: "C3"}); err == nil {
+		t.Fatal("expected no moves after the game ended")
+	}
+	if n := strings.Count(log.String(), "game-over game="+cg.ID); n != 2 {
+		t.Fatalf("expected both players to hear the result, got %d notices", n)
+	}
+
+	reloaded, err := store.Load(cg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Result != "B+T" || reloaded.Game.Result != "B+T" || reloaded.Game.MoveNumber() != 1 {
+		t.Fatalf("unexpected stored game %+v", reloaded)
+	}
+}
+
+func TestPassesAreScored(t *testing.T) {
+	store, _ := NewFileStore(t.TempDir())
+	m := NewManager(store, nil)
+	cg, err := m.Create("alice", "bob", Settings{Size: 5, Komi: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Play(cg.ID, "alice", gameapi.Action{Type: "play", Coord: "C3"})
+	m.Play(cg.ID, "bob", gameapi.Action{Type: "pass"})
+	cg, msg, err := m.Play(cg.ID, "alice", gameapi.Action{Type: "pass"})
+	if err != nil || !strings.Contains(msg, "game ends") {
+		t.Fatalf("unexpected final move %q (%v)", msg, err)
+	}
+	if cg.Result != "B+24.5" {
+		t.Fatalf("expected an area result, got %q", cg.Result)
+	}
+}
+
+func TestStaleCopiesAreNotSaved(t *testing.T) {
+	store, _ := NewFileStore(t.TempDir())
+	m := NewManager(store, nil)
+	cg, err := m.Create("alice", "bob", Settings{Size: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := store.Load(cg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Play(cg.ID, "alice", gameapi.Action{Type: "play", Coord: "E5"}); err != nil {
+		t.Fatal(err)
+	}
+	stale.Warned = true
+	if err := store.Save(stale); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for a stale copy, got %v", err)
+	}
+	if cg, _ = store.Load(cg.ID); cg.Game.MoveNumber() != 1 {
+		t.Fatalf("expected the move to survive, got %d moves", cg.Game.MoveNumber())
+	}
+}
+
+func TestCheckDeadlinesKeepsConcurrentMoves(t *testing.T) {
+	store, _ := NewFileStore(t.TempDir())
+	m := NewManager(store, nil)
+	// A warning period longer than the move time makes every check save.
+	m.Warning = 10 * Day
+	cg, err := m.Create("alice", "bob", Settings{Size: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := m.CheckDeadlines(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	players := []string{"alice", "bob"}
+	for i, coord := range []string{"A1", "B1", "C1", "D1", "E1", "F1"} {
+		if _, _, err := m.Play(cg.ID, players[i%2], gameapi.Action{Type: "play", Coord: coord}); err != nil {
+			t.Fatalf("move %s: %v", coord, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	if cg, _ = store.Load(cg.ID); cg.Game.MoveNumber() != 6 {
+		t.Fatalf("expected all 6 moves to be kept, got %d", cg.Game.MoveNumber())
+	}
+}
+
+func TestListSkipsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir)
+	m := NewManager(store, nil)
+	cg, err := m.Create("alice", "bob", Settings{Size: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"broken.json": "{", "not an id.json": "{}"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	games, err := store.List()
+	if err != nil || len(games) != 1 || games[0].ID != cg.ID {
+		t.Fatalf("expected only the real game to be listed, got %v (%v)", games, err)
+	}
+	if _, err := m.CheckDeadlines(); err != nil {
+		t.Fatalf("expected bad files not to stop deadline checks: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package correspondence
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventKind says why a player is being notified.
+type EventKind int
+
+const (
+	// YourTurn is sent when a game starts and after every move.
+	YourTurn EventKind = iota
+	// DeadlineWarning is sent once per move when the deadline is near.
+	DeadlineWarning
+	// GameOver is sent to both players when a game ends.
+	GameOver
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case YourTurn:
+		return "your-turn"
+	case DeadlineWarning:
+		return "deadline-warning"
+	case GameOver:
+		return "game-over"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a notification for one player.
+type Event struct {
+	Kind    EventKind
+	GameID  string
+	Player  string
+	Message string
+	Time    time.Time
+}
+
+// Notifier delivers events to players, for example by email or chat.
+type Notifier interface {
+	Notify(e Event) error
+}
+
+// LogNotifier writes each event as a line to W, such as a log file that
+// players or a mail script can follow.
+type LogNotifier struct {
+	mu sync.Mutex
+	W  io.Writer
+}
+
+// Notify appends the event to the log.
+func (n *LogNotifier) Notify(e Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err := fmt.Fprintf(n.W, "%s %s game=%s to=%s %s\n", e.Time.UTC().Format(time.RFC3339), e.Kind, e.GameID, e.Player, e.Message)
+	return err
+}
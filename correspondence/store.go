@@ -0,0 +1,124 @@
+package correspondence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"boardgame/gogame"
+	"boardgame/storage"
+)
+
+// Store keeps correspondence games between runs.
+type Store interface {
+	// Save stores cg and increments its Version. It returns ErrConflict,
+	// saving nothing, when cg.Version is not the version stored, or not 0
+	// for a new game.
+	Save(cg *Game) error
+	Load(id string) (*Game, error)
+	// List returns every stored game, most recently updated first.
+	List() ([]*Game, error)
+}
+
+// FileStore keeps each game as <id>.json in a directory, with the moves as
+// an SGF record beside the timing state.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+type record struct {
+	*Game
+	SGF string `json:"sgf"`
+}
+
+// Save writes cg, replacing the file atomically, unless the stored game has
+// moved on from cg's version.
+func (s *FileStore) Save(cg *Game) error {
+	if err := storage.CheckID(cg.ID); err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, cg.ID+".json")
+	var stored struct {
+		Version int `json:"version"`
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if stored.Version != cg.Version {
+		return fmt.Errorf("%w: %s", ErrConflict, cg.ID)
+	}
+	cg.Version++
+	data, err := json.MarshalIndent(record{Game: cg, SGF: gogame.WriteSGF(cg.Game)}, "", "  ")
+	if err == nil {
+		err = storage.WriteFile(path, append(data, '\n'))
+	}
+	if err != nil {
+		cg.Version--
+	}
+	return err
+}
+
+// Load reads the game stored under id.
+func (s *FileStore) Load(id string) (*Game, error) {
+	if err := storage.CheckID(id); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.Dir, id+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", storage.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec := record{Game: &Game{}}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	root, err := gogame.ParseSGF(rec.SGF)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if rec.Game.Game, err = gogame.GameFromSGF(root); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	rec.Game.ID = id
+	return rec.Game, nil
+}
+
+// List returns the stored games, most recently updated first. Unreadable
+// files are skipped.
+func (s *FileStore) List() ([]*Game, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []*Game
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		if storage.CheckID(id) != nil {
+			continue
+		}
+		if cg, err := s.Load(id); err == nil {
+			out = append(out, cg)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Updated.After(out[j].Updated) })
+	return out, nil
+}
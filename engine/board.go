@@ -8,68 +8,92 @@ type Position struct {
 	Col int
 }
 
-// Board stores a rectangular grid of player IDs.
-type Board struct {
+// Grid stores a rectangular grid of cells of any comparable type.
+// The zero value of T means the cell is empty.
+type Grid[T comparable] struct {
 	Rows  int
 	Cols  int
-	cells []int
+	cells []T
+}
+
+// Board stores a rectangular grid of player IDs, with 0 meaning empty.
+type Board = Grid[int]
+
+// Piece is a cell type for games that need more than an owner per cell, such
+// as kings in checkers or markers in Go. The zero Piece is an empty cell.
+type Piece struct {
+	Owner int    // player ID, 0 for none
+	Kind  int    // game-specific piece kind
+	Label string // optional annotation, e.g. a marker or move number
 }
 
 // NewBoard allocates a board with all cells empty (value 0).
 func NewBoard(rows, cols int) *Board {
+	return NewGrid[int](rows, cols)
+}
+
+// NewGrid allocates a grid with all cells set to the zero value of T.
+func NewGrid[T comparable](rows, cols int) *Grid[T] {
 	if rows <= 0 || cols <= 0 {
 		panic("board dimensions must be positive")
 	}
-	return &Board{
+	return &Grid[T]{
 		Rows:  rows,
 		Cols:  cols,
-		cells: make([]int, rows*cols),
+		cells: make([]T, rows*cols),
 	}
 }
 
 // Clone produces a deep copy of the board.
-func (b *Board) Clone() *Board {
-	copyCells := make([]int, len(b.cells))
+func (b *Grid[T]) Clone() *Grid[T] {
+	copyCells := make([]T, len(b.cells))
 	copy(copyCells, b.cells)
-	return &Board{
+	return &Grid[T]{
 		Rows:  b.Rows,
 		Cols:  b.Cols,
 		cells: copyCells,
 	}
 }
 
+// InBounds reports whether the position lies on the board.
+func (b *Grid[T]) InBounds(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < b.Rows && pos.Col >= 0 && pos.Col < b.Cols
+}
+
 // index converts a position into a linear index, returning an error for out of range coordinates.
-func (b *Board) index(pos Position) (int, error) {
-	if pos.Row < 0 || pos.Row >= b.Rows || pos.Col < 0 || pos.Col >= b.Cols {
+func (b *Grid[T]) index(pos Position) (int, error) {
+	if !b.InBounds(pos) {
 		return 0, fmt.Errorf("position out of bounds: %+v", pos)
 	}
 	return pos.Row*b.Cols + pos.Col, nil
 }
 
-// Get returns the player occupying the given cell (0 when empty).
-func (b *Board) Get(pos Position) (int, error) {
+// Get returns the contents of the given cell (the zero value when empty).
+func (b *Grid[T]) Get(pos Position) (T, error) {
 	idx, err := b.index(pos)
 	if err != nil {
-		return 0, err
+		var zero T
+		return zero, err
 	}
 	return b.cells[idx], nil
 }
 
-// Set writes the player ID into the given cell when empty.
-func (b *Board) Set(pos Position, playerID int) error {
+// Set writes the value into the given cell when empty.
+func (b *Grid[T]) Set(pos Position, value T) error {
 	idx, err := b.index(pos)
 	if err != nil {
 		return err
 	}
-	if b.cells[idx] != 0 {
+	var zero T
+	if b.cells[idx] != zero {
 		return fmt.Errorf("cell already occupied at %+v", pos)
 	}
-	b.cells[idx] = playerID
+	b.cells[idx] = value
 	return nil
 }
 
 // SetAt writes the value regardless of whether the cell is occupied. Primarily used by games that need to clear captures.
-func (b *Board) SetAt(pos Position, value int) error {
+func (b *Grid[T]) SetAt(pos Position, value T) error {
 	idx, err := b.index(pos)
 	if err != nil {
 		return err
@@ -78,10 +102,63 @@ func (b *Board) SetAt(pos Position, value int) error {
 	return nil
 }
 
+// Remove clears an occupied cell and returns what it held.
+func (b *Grid[T]) Remove(pos Position) (T, error) {
+	var zero T
+	idx, err := b.index(pos)
+	if err != nil {
+		return zero, err
+	}
+	old := b.cells[idx]
+	if old == zero {
+		return zero, fmt.Errorf("no piece to remove at %+v", pos)
+	}
+	b.cells[idx] = zero
+	return old, nil
+}
+
+// Replace swaps the contents of an occupied cell, returning the previous value.
+// Use it for promotions or changes of ownership.
+func (b *Grid[T]) Replace(pos Position, value T) (T, error) {
+	var zero T
+	idx, err := b.index(pos)
+	if err != nil {
+		return zero, err
+	}
+	old := b.cells[idx]
+	if old == zero {
+		return zero, fmt.Errorf("no piece to replace at %+v", pos)
+	}
+	b.cells[idx] = value
+	return old, nil
+}
+
+// Move relocates the contents of an occupied cell to an empty one.
+func (b *Grid[T]) Move(from, to Position) error {
+	var zero T
+	src, err := b.index(from)
+	if err != nil {
+		return err
+	}
+	dst, err := b.index(to)
+	if err != nil {
+		return err
+	}
+	if b.cells[src] == zero {
+		return fmt.Errorf("no piece to move at %+v", from)
+	}
+	if from != to && b.cells[dst] != zero {
+		return fmt.Errorf("cell already occupied at %+v", to)
+	}
+	b.cells[src], b.cells[dst] = zero, b.cells[src]
+	return nil
+}
+
 // IsFull reports whether all cells are occupied.
-func (b *Board) IsFull() bool {
+func (b *Grid[T]) IsFull() bool {
+	var zero T
 	for _, v := range b.cells {
-		if v == 0 {
+		if v == zero {
 			return false
 		}
 	}
@@ -89,7 +166,7 @@ func (b *Board) IsFull() bool {
 }
 
 // ForEach iterates over every position and stored value on the board.
-func (b *Board) ForEach(fn func(pos Position, value int)) {
+func (b *Grid[T]) ForEach(fn func(pos Position, value T)) {
 	for r := 0; r < b.Rows; r++ {
 		for c := 0; c < b.Cols; c++ {
 			idx := r*b.Cols + c
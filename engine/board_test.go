@@ -0,0 +1,111 @@
+package engine_test
+
+import (
+	"testing"
+
+	"boardgame/engine"
+)
+
+func TestGridSetRefusesOccupiedAndOutOfBoundsCells(t *testing.T) {
+	b := engine.NewBoard(2, 3)
+	if !b.InBounds(engine.Position{Row: 1, Col: 2}) {
+		t.Fatal("expected the far corner to be on the board")
+	}
+	for _, pos := range []engine.Position{{Row: -1, Col: 0}, {Row: 0, Col: -1}, {Row: 2, Col: 0}, {Row: 0, Col: 3}} {
+		if b.InBounds(pos) {
+			t.Fatalf("expected %+v to be off the board", pos)
+		}
+		if err := b.Set(pos, 1); err == nil {
+			t.Fatalf("expected setting %+v to fail", pos)
+		}
+	}
+	pos := engine.Position{Row: 0, Col: 1}
+	if err := b.Set(pos, 1); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := b.Set(pos, 2); err == nil {
+		t.Fatal("expected an occupied cell to be refused")
+	}
+	if err := b.SetAt(pos, 2); err != nil {
+		t.Fatalf("set at: %v", err)
+	}
+	if v, _ := b.Get(pos); v != 2 {
+		t.Fatalf("expected SetAt to overwrite the cell, got %d", v)
+	}
+}
+
+func TestGridRemoveAndReplace(t *testing.T) {
+	g := engine.NewGrid[engine.Piece](3, 3)
+	pos := engine.Position{Row: 1, Col: 1}
+	if _, err := g.Remove(pos); err == nil {
+		t.Fatal("expected removing from an empty cell to fail")
+	}
+	if _, err := g.Replace(pos, engine.Piece{Owner: 1, Kind: 2}); err == nil {
+		t.Fatal("expected replacing an empty cell to fail")
+	}
+	man := engine.Piece{Owner: 1, Kind: 1}
+	if err := g.Set(pos, man); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	king := engine.Piece{Owner: 1, Kind: 2, Label: "K"}
+	if old, err := g.Replace(pos, king); err != nil || old != man {
+		t.Fatalf("replace returned %+v, %v", old, err)
+	}
+	if old, err := g.Remove(pos); err != nil || old != king {
+		t.Fatalf("remove returned %+v, %v", old, err)
+	}
+	if p, _ := g.Get(pos); p != (engine.Piece{}) {
+		t.Fatalf("expected the cell to be empty, got %+v", p)
+	}
+	if _, err := g.Remove(engine.Position{Row: 3, Col: 0}); err == nil {
+		t.Fatal("expected removing off the board to fail")
+	}
+}
+
+func TestGridMove(t *testing.T) {
+	b := engine.NewBoard(3, 3)
+	from, to := engine.Position{Row: 0, Col: 0}, engine.Position{Row: 2, Col: 2}
+	if err := b.Move(from, to); err == nil {
+		t.Fatal("expected moving from an empty cell to fail")
+	}
+	_ = b.Set(from, 1)
+	_ = b.Set(to, 2)
+	if err := b.Move(from, to); err == nil {
+		t.Fatal("expected moving onto an occupied cell to fail")
+	}
+	if err := b.Move(from, engine.Position{Row: 0, Col: 3}); err == nil {
+		t.Fatal("expected moving off the board to fail")
+	}
+	if err := b.Move(from, from); err != nil {
+		t.Fatalf("moving a piece to its own cell: %v", err)
+	}
+	if v, _ := b.Get(from); v != 1 {
+		t.Fatalf("expected the piece to stay put, got %d", v)
+	}
+	mid := engine.Position{Row: 1, Col: 1}
+	if err := b.Move(from, mid); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if v, _ := b.Get(from); v != 0 {
+		t.Fatalf("expected the origin to be empty, got %d", v)
+	}
+	if v, _ := b.Get(mid); v != 1 {
+		t.Fatalf("expected the piece at the destination, got %d", v)
+	}
+}
+
+func TestGridCloneAndIsFull(t *testing.T) {
+	b := engine.NewBoard(1, 2)
+	_ = b.Set(engine.Position{Row: 0, Col: 0}, 1)
+	if b.IsFull() {
+		t.Fatal("expected a board with an empty cell not to be full")
+	}
+	c := b.Clone()
+	_ = c.Set(engine.Position{Row: 0, Col: 1}, 2)
+	if !c.IsFull() {
+		t.Fatal("expected the clone to be full")
+	}
+	if b.IsFull() {
+		t.Fatal("expected the original to be unchanged by the clone")
+	}
+}
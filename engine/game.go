@@ -4,15 +4,18 @@ import "fmt"
 
 // Player represents a participant in a game.
 type Player struct {
-	ID    int
-	Name  string
-	Token string // short symbol for display, e.g. "X"
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token,omitempty"` // short symbol for display, e.g. "X"
 }
 
 // Move represents an action on the board.
 type Move struct {
 	PlayerID int
 	Pos      Position
+	// Path lists the squares visited by a piece in movement games, starting at
+	// its origin and ending at Pos. Placement games leave it empty.
+	Path []Position
 }
 
 // Outcome captures the result of a completed game.
@@ -28,6 +31,14 @@ type Game struct {
 	currentIndex int
 	Log          []Move
 	Outcome      Outcome
+	// QuietMoves counts consecutive moves a rule considers non-progressing,
+	// such as the draughts 40-move rule. Rules reset it as they see fit.
+	QuietMoves int
+	// Pieces holds the cells of games that need more than an owner per
+	// cell, such as kings in checkers; other games leave it nil. Such games
+	// keep Board's owners in step so that code reading only Board still sees
+	// who holds each cell.
+	Pieces *Grid[Piece]
 }
 
 // NewGame constructs a Game with the provided board and players.
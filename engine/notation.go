@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Notation reads and writes a game's moves as text. G is the game state a
+// move is read against and M the move type, so games with their own state,
+// such as Go, implement it too. ParseMove and FormatMove are inverses.
+type Notation[G, M any] interface {
+	ParseMove(g G, text string) (M, error)
+	FormatMove(g G, m M) string
+}
+
+// RowCol is the generic notation for placement games: the 1-based row and
+// column of the destination counted from the top left, written "2,3" and
+// also read as "2 3". Moves are resolved against Rule's valid moves.
+type RowCol struct {
+	Rule Rule
+}
+
+// ParseMove implements Notation.
+func (n RowCol) ParseMove(g *Game, text string) (Move, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) != 2 {
+		return Move{}, fmt.Errorf("enter a row and a column, e.g. 2 3")
+	}
+	row, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Move{}, fmt.Errorf("invalid row %q", fields[0])
+	}
+	col, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Move{}, fmt.Errorf("invalid column %q", fields[1])
+	}
+	return MoveTo(n.Rule, g, Position{Row: row - 1, Col: col - 1}, text)
+}
+
+// FormatMove implements Notation.
+func (RowCol) FormatMove(_ *Game, m Move) string {
+	return fmt.Sprintf("%d,%d", m.Pos.Row+1, m.Pos.Col+1)
+}
+
+// MoveTo returns the current player's valid move to pos, naming the move by
+// text in the error when there is none.
+func MoveTo(rule Rule, g *Game, pos Position, text string) (Move, error) {
+	for _, m := range rule.ValidMoves(g) {
+		if m.Pos == pos {
+			return m, nil
+		}
+	}
+	return Move{}, fmt.Errorf("%s is not a legal move", strings.TrimSpace(text))
+}
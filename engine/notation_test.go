@@ -0,0 +1,32 @@
+package engine_test
+
+import (
+	"testing"
+
+	"boardgame/engine"
+	"boardgame/tictactoe"
+)
+
+func TestRowColRoundTrips(t *testing.T) {
+	rules := tictactoe.NewRules()
+	g, err := rules.NewGame([]engine.Player{{ID: 1}, {ID: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := engine.RowCol{Rule: rules}
+	for _, text := range []string{"1,1", "3 2", " 2 ,3 "} {
+		m, err := n.ParseMove(g, text)
+		if err != nil {
+			t.Fatalf("parse %q: %v", text, err)
+		}
+		again, err := n.ParseMove(g, n.FormatMove(g, m))
+		if err != nil || again.Pos != m.Pos {
+			t.Fatalf("%q did not round-trip: %+v vs %+v (%v)", text, m, again, err)
+		}
+	}
+	for _, text := range []string{"", "2", "a,b", "4,1", "1,2,3"} {
+		if _, err := n.ParseMove(g, text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Record is a JSON game log for a registered game: the game's name, its
+// players in turn order, and the moves written in the game's notation.
+type Record struct {
+	Game    string   `json:"game"`
+	Players []Player `json:"players"`
+	Moves   []string `json:"moves"`
+}
+
+// NewRecord logs the moves played so far in g, a game of def.
+func NewRecord(def GameDefinition, g *Game) Record {
+	moves := make([]string, len(g.Log))
+	for i, m := range g.Log {
+		moves[i] = def.Notation.FormatMove(g, m)
+	}
+	return Record{Game: def.Name, Players: g.Players, Moves: moves}
+}
+
+// ParseRecord decodes a record written by encoding/json.
+func ParseRecord(data []byte) (Record, error) {
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Record{}, fmt.Errorf("reading game record: %w", err)
+	}
+	if r.Game == "" {
+		return Record{}, fmt.Errorf("game record does not name its game")
+	}
+	return r, nil
+}
+
+// Replay starts the recorded game over and plays its first n moves,
+// returning the game's definition and the resulting position.
+func (r Record) Replay(n int) (GameDefinition, *Game, error) {
+	def, ok := Lookup(r.Game)
+	if !ok {
+		return GameDefinition{}, nil, fmt.Errorf("unknown game %q", r.Game)
+	}
+	if n < 0 || n > len(r.Moves) {
+		return GameDefinition{}, nil, fmt.Errorf("move %d is outside the record's %d moves", n, len(r.Moves))
+	}
+	g, err := def.NewGame(r.Players)
+	if err != nil {
+		return GameDefinition{}, nil, err
+	}
+	for i, text := range r.Moves[:n] {
+		m, err := def.Notation.ParseMove(g, text)
+		if err == nil {
+			err = def.Rule.ApplyMove(g, m)
+		}
+		if err != nil {
+			return GameDefinition{}, nil, fmt.Errorf("move %d (%s): %w", i+1, text, err)
+		}
+		g.AdvanceTurn()
+	}
+	return def, g, nil
+}
@@ -0,0 +1,57 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	_ "boardgame/tictactoe"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	def, _ := engine.Lookup("ttt")
+	g, err := def.NewGame(def.Players)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	for _, text := range []string{"b2", "a1", "c3"} {
+		m, err := def.Notation.ParseMove(g, text)
+		if err != nil {
+			t.Fatalf("parse %s: %v", text, err)
+		}
+		if err := def.Rule.ApplyMove(g, m); err != nil {
+			t.Fatalf("apply %s: %v", text, err)
+		}
+		g.AdvanceTurn()
+	}
+
+	data, err := json.Marshal(engine.NewRecord(def, g))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	rec, err := engine.ParseRecord(data)
+	if err != nil {
+		t.Fatalf("parse record %s: %v", data, err)
+	}
+	if got := strings.Join(rec.Moves, " "); got != "b2 a1 c3" {
+		t.Fatalf("expected moves b2 a1 c3, got %s", got)
+	}
+	_, replayed, err := rec.Replay(2)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(replayed.Log) != 2 || replayed.CurrentPlayer().ID != def.Players[0].ID {
+		t.Fatalf("expected two moves with the first player to move, got %d moves and %+v", len(replayed.Log), replayed.CurrentPlayer())
+	}
+	if _, _, err := rec.Replay(4); err == nil {
+		t.Fatal("expected replaying past the end of the record to fail")
+	}
+	if _, err := engine.ParseRecord([]byte(`{"moves":["b2"]}`)); err == nil {
+		t.Fatal("expected a record without a game name to be rejected")
+	}
+	rec.Moves[1] = "b2"
+	if _, _, err := rec.Replay(3); err == nil || !strings.Contains(err.Error(), "move 2") {
+		t.Fatalf("expected the repeated move to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GameDefinition describes a game built on Rule so that tools can find it by
+// name. Rules packages register one from an init function; importing the
+// package (for its side effects, if nothing else) makes the game available.
+type GameDefinition struct {
+	Name       string // short lowercase name used on command lines, e.g. "ttt"
+	Title      string // display name, e.g. "Tic-tac-toe"
+	MinPlayers int
+	MaxPlayers int
+	Rows, Cols int      // default board dimensions
+	Players    []Player // default seats, in turn order
+	NewGame    func(players []Player) (*Game, error)
+	Rule       Rule
+	Render     func(g *Game) string
+	Notation   Notation[*Game, Move]
+	MoveHelp   string // how to type a move, e.g. "row and column, e.g. 2 3"
+}
+
+var registry = struct {
+	sync.RWMutex
+	games map[string]GameDefinition
+}{games: map[string]GameDefinition{}}
+
+// Register adds a game to the registry. It panics if the definition is
+// incomplete or its name is taken, since both are programming errors.
+func Register(def GameDefinition) {
+	if err := def.validate(); err != nil {
+		panic("engine: " + err.Error())
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.games[def.Name]; dup {
+		panic("engine: game " + def.Name + " registered twice")
+	}
+	registry.games[def.Name] = def
+}
+
+// Lookup returns the game registered under name, ignoring case.
+func Lookup(name string) (GameDefinition, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	def, ok := registry.games[strings.ToLower(name)]
+	return def, ok
+}
+
+// Games returns every registered game, sorted by name.
+func Games() []GameDefinition {
+	registry.RLock()
+	defer registry.RUnlock()
+	out := make([]GameDefinition, 0, len(registry.games))
+	for _, def := range registry.games {
+		out = append(out, def)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (d GameDefinition) validate() error {
+	switch {
+	case d.Name == "" || d.Name != strings.ToLower(d.Name) || strings.ContainsAny(d.Name, " \t"):
+		return fmt.Errorf("game name %q must be a lowercase word", d.Name)
+	case d.NewGame == nil || d.Rule == nil || d.Render == nil || d.Notation == nil:
+		return fmt.Errorf("game %s needs NewGame, Rule, Render, and Notation", d.Name)
+	case d.MinPlayers < 1 || d.MaxPlayers < d.MinPlayers:
+		return fmt.Errorf("game %s has an invalid player range %d-%d", d.Name, d.MinPlayers, d.MaxPlayers)
+	case len(d.Players) < d.MinPlayers || len(d.Players) > d.MaxPlayers:
+		return fmt.Errorf("game %s has %d default players, outside %d-%d", d.Name, len(d.Players), d.MinPlayers, d.MaxPlayers)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package engine_test
+
+import (
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	_ "boardgame/tictactoe"
+)
+
+func TestRegisteredGamesAreDiscoverable(t *testing.T) {
+	def, ok := engine.Lookup("TTT")
+	if !ok {
+		t.Fatal("expected tictactoe to register itself as ttt")
+	}
+	g, err := def.NewGame(def.Players)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if g.Board.Rows != def.Rows || g.Board.Cols != def.Cols {
+		t.Fatalf("board is %dx%d, definition says %dx%d", g.Board.Rows, g.Board.Cols, def.Rows, def.Cols)
+	}
+	m, err := def.Notation.ParseMove(g, "c2")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if m.Pos != (engine.Position{Row: 1, Col: 2}) || m.PlayerID != 1 {
+		t.Fatalf("unexpected move %+v", m)
+	}
+	if got := def.Notation.FormatMove(g, m); got != "c2" {
+		t.Fatalf("expected c2, got %s", got)
+	}
+	if err := def.Rule.ApplyMove(g, m); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	g.AdvanceTurn()
+	// Numpad 6 is the middle of the right column, which X just took.
+	if _, err := def.Notation.ParseMove(g, "6"); err == nil || !strings.Contains(err.Error(), "not a legal move") {
+		t.Fatalf("expected an occupied cell to be rejected, got %v", err)
+	}
+
+	var names []string
+	for _, d := range engine.Games() {
+		names = append(names, d.Name)
+	}
+	if strings.Join(names, ",") != "ttt" {
+		t.Fatalf("expected only ttt to be registered in this test binary, got %v", names)
+	}
+}
+
+func TestRegisterRejectsBadDefinitions(t *testing.T) {
+	def, _ := engine.Lookup("ttt")
+	for name, d := range map[string]engine.GameDefinition{
+		"duplicate":   def,
+		"bad name":    withName(def, "Tic Tac"),
+		"no players":  withPlayers(def, nil),
+		"no renderer": withoutRender(def),
+		"no notation": withoutNotation(def),
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Register to panic", name)
+				}
+			}()
+			engine.Register(d)
+		}()
+	}
+}
+
+func withName(d engine.GameDefinition, name string) engine.GameDefinition {
+	d.Name = name
+	return d
+}
+
+func withPlayers(d engine.GameDefinition, players []engine.Player) engine.GameDefinition {
+	d.Name, d.Players = "other", players
+	return d
+}
+
+func withoutRender(d engine.GameDefinition) engine.GameDefinition {
+	d.Name, d.Render = "other", nil
+	return d
+}
+
+func withoutNotation(d engine.GameDefinition) engine.GameDefinition {
+	d.Name, d.Notation = "other", nil
+	return d
+}
@@ -0,0 +1,95 @@
+package gameapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"boardgame/gogame"
+)
+
+// Bridge is the single game a browser page plays through the WebAssembly
+// build (cmd/wasm). Its methods take and return JSON so that the syscall/js
+// layer only passes strings; failures come back as {"error": "..."}.
+type Bridge struct {
+	game *gogame.Game
+}
+
+// BridgeResponse is the reply to NewGame, Act, and State.
+type BridgeResponse struct {
+	State   *State `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+var errNoGame = errors.New("no game started")
+
+// NewGame replaces the current game with one described by optionsJSON, an
+// Options object ("" or "{}" for a 19x19 standard game).
+func (b *Bridge) NewGame(optionsJSON string) string {
+	var o Options
+	if optionsJSON != "" {
+		if err := json.Unmarshal([]byte(optionsJSON), &o); err != nil {
+			return errorJSON(err)
+		}
+	}
+	g, err := NewGame(o)
+	if err != nil {
+		return errorJSON(err)
+	}
+	b.game = g
+	return b.State()
+}
+
+// Act applies actionJSON, an Action object, to the current game.
+func (b *Bridge) Act(actionJSON string) string {
+	if b.game == nil {
+		return errorJSON(errNoGame)
+	}
+	var a Action
+	if err := json.Unmarshal([]byte(actionJSON), &a); err != nil {
+		return errorJSON(err)
+	}
+	msg, err := Apply(b.game, a)
+	if err != nil {
+		return errorJSON(err)
+	}
+	state := NewState(b.game)
+	return toJSON(BridgeResponse{State: &state, Message: msg})
+}
+
+// State returns the current game's state.
+func (b *Bridge) State() string {
+	if b.game == nil {
+		return errorJSON(errNoGame)
+	}
+	state := NewState(b.game)
+	return toJSON(BridgeResponse{State: &state})
+}
+
+// Score returns the current game's Score by area with the given komi.
+func (b *Bridge) Score(komi float64) string {
+	if b.game == nil {
+		return errorJSON(errNoGame)
+	}
+	return toJSON(NewScore(b.game, komi))
+}
+
+// SGF returns the current game's record, or "" before a game is started.
+func (b *Bridge) SGF() string {
+	if b.game == nil {
+		return ""
+	}
+	return gogame.WriteSGF(b.game)
+}
+
+func errorJSON(err error) string {
+	return toJSON(BridgeResponse{Error: err.Error()})
+}
+
+func toJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return `{"error":"internal error"}`
+	}
+	return string(data)
+}
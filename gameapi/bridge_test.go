@@ -0,0 +1,57 @@
+package gameapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeBridge(t *testing.T, out string) BridgeResponse {
+	t.Helper()
+	var resp BridgeResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode %s: %v", out, err)
+	}
+	return resp
+}
+
+func TestBridge(t *testing.T) {
+	var b Bridge
+	if resp := decodeBridge(t, b.Act(`{"type":"pass"}`)); resp.Error == "" {
+		t.Fatal("expected an error before a game is started")
+	}
+	if resp := decodeBridge(t, b.NewGame(`{"size":9,"variant":"chess"}`)); resp.Error == "" {
+		t.Fatal("expected an error for an unknown variant")
+	}
+
+	resp := decodeBridge(t, b.NewGame(`{"size":9}`))
+	if resp.Error != "" || resp.State.Rows != 9 || resp.State.ToPlay != "black" {
+		t.Fatalf("unexpected new game %+v", resp)
+	}
+	resp = decodeBridge(t, b.Act(`{"type":"play","coord":"E5"}`))
+	if resp.Message != "Black played E5." || resp.State.Board[4][4] != 1 {
+		t.Fatalf("unexpected play %+v", resp)
+	}
+	if resp = decodeBridge(t, b.Act(`{"type":"play","row":4,"col":4}`)); resp.Error == "" {
+		t.Fatal("expected an error playing on an occupied point")
+	}
+	if resp = decodeBridge(t, b.Act(`{"type":"pass"}`)); resp.State.ToPlay != "black" {
+		t.Fatalf("expected Black to play after White passed, got %+v", resp)
+	}
+	if !strings.Contains(b.SGF(), ";B[ee]") {
+		t.Fatalf("SGF missing the first move: %s", b.SGF())
+	}
+
+	var score Score
+	if err := json.Unmarshal([]byte(b.Score(6.5)), &score); err != nil {
+		t.Fatal(err)
+	}
+	if score.Black != 81 || score.Result != "B+74.5" {
+		t.Fatalf("unexpected score %+v", score)
+	}
+
+	decodeBridge(t, b.Act(`{"type":"undo"}`))
+	if resp = decodeBridge(t, b.Act(`{"type":"undo"}`)); resp.State.MoveNumber != 0 || resp.State.Board[4][4] != 0 {
+		t.Fatalf("expected an empty board after two undos, got %+v", resp)
+	}
+}
@@ -0,0 +1,172 @@
+// Package gameapi is the JSON-facing view of a gogame.Game shared by the HTTP
+// server and browser clients, so every frontend plays by the same Go rules.
+package gameapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// ErrBadAction is returned for actions that are malformed rather than illegal.
+var ErrBadAction = errors.New("bad action")
+
+// Point is a board intersection; row 0 is the top row.
+type Point struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// State is a snapshot of a game for display.
+type State struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+	// Board holds one row per line from the top: 0 is empty, 1 Black, 2 White.
+	Board             [][]int        `json:"board"`
+	ToPlay            string         `json:"toPlay"` // "black", "white", or "" once the game is over
+	Captures          map[string]int `json:"captures"`
+	MoveNumber        int            `json:"moveNumber"`
+	ConsecutivePasses int            `json:"consecutivePasses"`
+	LastMove          *Point         `json:"lastMove,omitempty"`
+	Over              bool           `json:"over"`
+	Result            string         `json:"result,omitempty"`
+}
+
+// NewState captures the current state of g.
+func NewState(g *gogame.Game) State {
+	s := State{
+		Rows:              g.Rows,
+		Cols:              g.Cols,
+		Board:             make([][]int, g.Rows),
+		ToPlay:            colorName(g.ToPlay),
+		Captures:          map[string]int{"black": g.Captures[gogame.Black], "white": g.Captures[gogame.White]},
+		MoveNumber:        g.MoveNumber(),
+		ConsecutivePasses: g.ConsecutivePasses,
+		Result:            g.Result,
+	}
+	for r := range s.Board {
+		s.Board[r] = make([]int, g.Cols)
+	}
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		s.Board[pos.Row][pos.Col] = v
+	})
+	if last, ok := g.LastMove(); ok && !last.Pass {
+		s.LastMove = &Point{Row: last.Pos.Row, Col: last.Pos.Col}
+	}
+	_, s.Over = g.Status()
+	return s
+}
+
+// Score is an area count for display.
+type Score struct {
+	Black  float64 `json:"black"`
+	White  float64 `json:"white"`
+	Komi   float64 `json:"komi"`
+	Result string  `json:"result"`
+	Dead   []Point `json:"dead"`
+}
+
+// NewScore scores g by area with the given komi.
+func NewScore(g *gogame.Game, komi float64) Score {
+	sc := gogame.ScoreArea(g, komi)
+	s := Score{Black: sc.Black, White: sc.White, Komi: sc.Komi, Result: sc.Result(), Dead: []Point{}}
+	for _, p := range sc.Dead {
+		s.Dead = append(s.Dead, Point{Row: p.Row, Col: p.Col})
+	}
+	return s
+}
+
+// Options describes a new game. Size defaults to 19 and Cols to Size.
+type Options struct {
+	Size     int    `json:"size"`
+	Cols     int    `json:"cols"`
+	Variant  string `json:"variant"`
+	Captures int    `json:"captures"`
+}
+
+// NewGame starts the game o describes.
+func NewGame(o Options) (*gogame.Game, error) {
+	if o.Size == 0 {
+		o.Size = 19
+	}
+	if o.Cols == 0 {
+		o.Cols = o.Size
+	}
+	variant, err := gogame.ParseVariant(o.Variant)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gogame.NewRectGame(o.Size, o.Cols)
+	if err != nil {
+		return nil, err
+	}
+	g.Variant = variant
+	g.CaptureGoal = o.Captures
+	return g, nil
+}
+
+// Action is a request to change a game: "play" (at Row/Col, or at Coord such
+// as "D4" when it is set), "pass", "undo", or "resign".
+type Action struct {
+	Type  string `json:"type"`
+	Row   int    `json:"row"`
+	Col   int    `json:"col"`
+	Coord string `json:"coord,omitempty"`
+}
+
+// Apply performs an action for the player to move and returns a log line
+// describing it. Actions the rules forbid return the rule's error unchanged;
+// malformed ones wrap ErrBadAction.
+func Apply(g *gogame.Game, a Action) (string, error) {
+	mover := g.ToPlay
+	switch strings.ToLower(a.Type) {
+	case "play":
+		pos := engine.Position{Row: a.Row, Col: a.Col}
+		if a.Coord != "" {
+			var err error
+			if pos, err = gogame.ParseCoordRect(a.Coord, g.Rows, g.Cols); err != nil {
+				return "", fmt.Errorf("%w: %v", ErrBadAction, err)
+			}
+		}
+		res, err := g.PlayMove(pos)
+		if err != nil {
+			return "", err
+		}
+		msg := fmt.Sprintf("%s played %s", mover, gogame.FormatCoord(pos, g.Rows, g.Cols))
+		if res.Captured > 0 {
+			msg += fmt.Sprintf(", captured %d", res.Captured)
+		}
+		return msg + ".", nil
+	case "pass":
+		if mover == gogame.None {
+			return "", fmt.Errorf("game is finished")
+		}
+		g.Pass()
+		if g.ConsecutivePasses >= 2 {
+			return fmt.Sprintf("%s passed. Both players passed - game ends.", mover), nil
+		}
+		return fmt.Sprintf("%s passed.", mover), nil
+	case "undo":
+		if err := g.Undo(); err != nil {
+			return "", err
+		}
+		return "Undid last move.", nil
+	case "resign":
+		if err := g.Resign(mover); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s resigned.", mover), nil
+	default:
+		return "", fmt.Errorf("%w: unknown action %q", ErrBadAction, a.Type)
+	}
+}
+
+func colorName(c gogame.Color) string {
+	if c == gogame.None {
+		return ""
+	}
+	return strings.ToLower(c.String())
+}
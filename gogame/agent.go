@@ -0,0 +1,88 @@
+package gogame
+
+import (
+	"errors"
+	"math/rand"
+
+	"boardgame/engine"
+)
+
+// Agent chooses the next move for the player to move in a Go game.
+type Agent interface {
+	GenMove(g *Game) (Move, error)
+}
+
+// IsEyeLike reports whether pos is empty and every orthogonal neighbor is a stone of color.
+func IsEyeLike(b *engine.Board, pos engine.Position, color Color) bool {
+	if v, err := b.Get(pos); err != nil || v != 0 {
+		return false
+	}
+	for _, n := range neighbors(b, pos) {
+		if v, _ := b.Get(n); Color(v) != color {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEye reports whether pos is a true eye of color: eye-like, with at most one
+// diagonal held by the opponent in the middle of the board and none on the
+// edge or in the corner. Filling such a point never helps its owner.
+func IsEye(b *engine.Board, pos engine.Position, color Color) bool {
+	if !IsEyeLike(b, pos, color) {
+		return false
+	}
+	opponent, offBoard := 0, 0
+	for _, d := range []engine.Position{{Row: -1, Col: -1}, {Row: -1, Col: 1}, {Row: 1, Col: -1}, {Row: 1, Col: 1}} {
+		diag := engine.Position{Row: pos.Row + d.Row, Col: pos.Col + d.Col}
+		v, err := b.Get(diag)
+		if err != nil {
+			offBoard++
+			continue
+		}
+		if Color(v) == other(color) {
+			opponent++
+		}
+	}
+	if offBoard > 0 {
+		return opponent == 0
+	}
+	return opponent <= 1
+}
+
+// PlayoutAgent is a light playout policy: it plays a uniformly random legal
+// move that does not fill one of its own true eyes, and passes when none remain.
+// Games between two PlayoutAgents end quickly with every group settled, which
+// makes it suitable for Monte Carlo rollouts.
+type PlayoutAgent struct {
+	Rand *rand.Rand
+}
+
+// GenMove implements Agent.
+func (a *PlayoutAgent) GenMove(g *Game) (Move, error) {
+	if g.ToPlay == None {
+		return Move{}, errors.New("game is finished")
+	}
+	r := a.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(rand.Int63())) //nolint:gosec // best-effort randomness for simulations
+	}
+	var candidates []engine.Position
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		if v == 0 {
+			candidates = append(candidates, pos)
+		}
+	})
+	// Test candidates in random order and stop at the first acceptable one,
+	// since legality checks are the expensive part.
+	for i := len(candidates) - 1; i >= 0; i-- {
+		j := r.Intn(i + 1)
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+		pos := candidates[i]
+		if IsEye(g.Board, pos, g.ToPlay) || !g.IsLegal(pos) {
+			continue
+		}
+		return Move{Color: g.ToPlay, Pos: pos}, nil
+	}
+	return Move{Color: g.ToPlay, Pass: true}, nil
+}
@@ -0,0 +1,158 @@
+package gogame
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"boardgame/engine"
+)
+
+func TestEyeDetection(t *testing.T) {
+	g := diagram(t,
+		". X . X O",
+		"X X X O .",
+		"X . X . .",
+		"X X O . .",
+		". . . . .",
+	)
+	cases := []struct {
+		coord string
+		eye   bool
+	}{
+		{"A5", true},  // corner eye with a friendly diagonal
+		{"C5", false}, // edge point with an opponent diagonal is a false eye
+		{"B3", true},  // one opponent diagonal is fine in the middle
+		{"E4", false}, // not surrounded
+	}
+	for _, c := range cases {
+		if got := IsEye(g.Board, coord(t, g, c.coord), Black); got != c.eye {
+			t.Fatalf("IsEye(%s) = %v, want %v", c.coord, got, c.eye)
+		}
+	}
+}
+
+func TestPlayoutAgentFinishesGames(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 5; i++ {
+		g, err := NewGame(9)
+		if err != nil {
+			t.Fatalf("new game: %v", err)
+		}
+		played := Playout(g, rng, 1000)
+		if played >= 1000 {
+			t.Fatalf("playout %d did not finish", i)
+		}
+		// Without eye filling, the game ends with groups that can no longer be captured.
+		res := PassAlive(g)
+		if len(res.Chains[Black])+len(res.Chains[White]) == 0 {
+			t.Fatalf("playout %d ended without any pass-alive groups", i)
+		}
+	}
+}
+
+func TestIsLegalAgreesWithPlace(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	policy := &PlayoutAgent{Rand: rng}
+	for game := 0; game < 3; game++ {
+		g, _ := NewGame(7)
+		for g.ToPlay != None && g.ConsecutivePasses < 2 {
+			g.Board.ForEach(func(pos engine.Position, _ int) {
+				next, _, err := place(g.Board, g.ToPlay, pos)
+				want := err == nil && g.history[serialize(next, other(g.ToPlay))] == 0
+				if got := g.IsLegal(pos); got != want {
+					t.Fatalf("IsLegal(%+v) = %v, want %v on\n%s", pos, got, want, RenderBoardASCII(g))
+				}
+			})
+			m, _ := policy.GenMove(g)
+			g.apply(m)
+		}
+	}
+}
+
+func TestMCTSAgentCapturesGroupInAtari(t *testing.T) {
+	// The White stones on C3 and C2 have one liberty left, at D2.
+	g := diagram(t,
+		". . . . .",
+		". . X . .",
+		". X O X .",
+		". X O . .",
+		". . X . .",
+	)
+	agent := &MCTSAgent{Playouts: 400, Komi: 0.5, Rand: rand.New(rand.NewSource(1))}
+	m, err := agent.GenMove(g)
+	if err != nil {
+		t.Fatalf("genmove: %v", err)
+	}
+	if m.Pass || m.Color != Black || m.Pos != coord(t, g, "D2") {
+		t.Fatalf("expected Black to capture at D2, got %+v", m)
+	}
+}
+
+func TestMCTSAgentStopsAtItsTimeLimit(t *testing.T) {
+	g, _ := NewGame(19)
+	agent := &MCTSAgent{Playouts: 1 << 20, Time: 50 * time.Millisecond, Rand: rand.New(rand.NewSource(1))}
+	start := time.Now()
+	if _, err := agent.GenMove(g); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Fatalf("expected the search to stop near 50ms, took %v", took)
+	}
+	if n := defaultPlayouts(19, 19); n >= DefaultPlayouts || n < minPlayouts {
+		t.Fatalf("expected fewer default playouts on 19x19, got %d", n)
+	}
+	if n := defaultPlayouts(9, 9); n != DefaultPlayouts {
+		t.Fatalf("expected %d default playouts on 9x9, got %d", DefaultPlayouts, n)
+	}
+}
+
+func BenchmarkPlayout(b *testing.B) {
+	for _, size := range []int{9, 19} {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < b.N; i++ {
+				g, _ := NewGame(size)
+				Playout(g, rng, 3*size*size)
+			}
+		})
+	}
+}
+
+// BenchmarkPlayoutSettleCheck compares checking for a settled board with
+// Benson's algorithm from various points in a playout: always, once fewer
+// than 1/4 or 1/8 of the points are empty, or never. It reports the moves
+// each playout saves alongside the time it costs.
+func BenchmarkPlayoutSettleCheck(b *testing.B) {
+	for _, size := range []int{9, 19} {
+		for _, fraction := range []int{1, 4, playoutSettleFraction, 0} {
+			b.Run(fmt.Sprintf("%dx%d/fraction=%d", size, size, fraction), func(b *testing.B) {
+				rng := rand.New(rand.NewSource(1))
+				policy := &PlayoutAgent{Rand: rng}
+				moves := 0
+				for i := 0; i < b.N; i++ {
+					g, _ := NewGame(size)
+					moves += playout(g, policy, 3*size*size, fraction)
+				}
+				b.ReportMetric(float64(moves)/float64(b.N), "moves/op")
+			})
+		}
+	}
+}
+
+func BenchmarkMCTSAgent(b *testing.B) {
+	for _, size := range []int{9, 19} {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			// Search from the middle game, where the game has a long history.
+			g, _ := NewGame(size)
+			Playout(g, rand.New(rand.NewSource(1)), size*size/2)
+			agent := &MCTSAgent{Playouts: 100, Komi: 6.5, Rand: rand.New(rand.NewSource(1))}
+			for i := 0; i < b.N; i++ {
+				if _, err := agent.GenMove(g); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
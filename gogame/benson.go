@@ -0,0 +1,188 @@
+package gogame
+
+import "boardgame/engine"
+
+// PassAliveResult holds the outcome of Benson's algorithm for both colors.
+type PassAliveResult struct {
+	// Chains lists each color's unconditionally alive chains: they cannot be
+	// captured even if their owner passes every turn.
+	Chains map[Color][][]engine.Position
+	// Territory lists points enclosed by a color's pass-alive chains where the
+	// opponent can never live. Opponent stones inside are dead.
+	Territory map[Color][]engine.Position
+	owner     map[engine.Position]Color
+}
+
+// Owner reports which color a point is settled for (a pass-alive stone or
+// territory), or None if it is still open.
+func (r PassAliveResult) Owner(pos engine.Position) Color {
+	return r.owner[pos]
+}
+
+// Settled reports how many points of the board are settled.
+func (r PassAliveResult) Settled() int {
+	return len(r.owner)
+}
+
+// PassAlive runs Benson's unconditional-life algorithm on the current board.
+func PassAlive(g *Game) PassAliveResult {
+	res := PassAliveResult{
+		Chains:    map[Color][][]engine.Position{},
+		Territory: map[Color][]engine.Position{},
+		owner:     map[engine.Position]Color{},
+	}
+	for _, color := range []Color{Black, White} {
+		chains, territory := benson(g.Board, color)
+		res.Chains[color] = chains
+		res.Territory[color] = territory
+		for _, chain := range chains {
+			for _, p := range chain {
+				res.owner[p] = color
+			}
+		}
+		for _, p := range territory {
+			res.owner[p] = color
+		}
+	}
+	return res
+}
+
+// bensonRegion is a maximal connected set of points not occupied by the color being analysed.
+type bensonRegion struct {
+	points []engine.Position
+	empty  []engine.Position
+	chains map[int]bool // indexes of bordering chains
+}
+
+// benson returns the pass-alive chains of color and the points of the regions
+// they enclose in which every empty point touches one of those chains.
+func benson(b *engine.Board, color Color) ([][]engine.Position, []engine.Position) {
+	chainOf := map[engine.Position]int{}
+	var chains [][]engine.Position
+	var chainLibs []map[engine.Position]struct{}
+	b.ForEach(func(pos engine.Position, v int) {
+		if Color(v) != color {
+			return
+		}
+		if _, ok := chainOf[pos]; ok {
+			return
+		}
+		group, libs, _ := collectGroup(b, pos)
+		for _, p := range group {
+			chainOf[p] = len(chains)
+		}
+		chains = append(chains, group)
+		chainLibs = append(chainLibs, libs)
+	})
+
+	var regions []*bensonRegion
+	seen := map[engine.Position]bool{}
+	b.ForEach(func(pos engine.Position, v int) {
+		if Color(v) == color || seen[pos] {
+			return
+		}
+		region := &bensonRegion{chains: map[int]bool{}}
+		stack := []engine.Position{pos}
+		seen[pos] = true
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			region.points = append(region.points, cur)
+			if cv, _ := b.Get(cur); cv == 0 {
+				region.empty = append(region.empty, cur)
+			}
+			for _, n := range neighbors(b, cur) {
+				nv, _ := b.Get(n)
+				if Color(nv) == color {
+					region.chains[chainOf[n]] = true
+					continue
+				}
+				if !seen[n] {
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+		regions = append(regions, region)
+	})
+
+	// vital reports whether every empty point of the region is a liberty of the chain.
+	vital := func(r *bensonRegion, chain int) bool {
+		if !r.chains[chain] {
+			return false
+		}
+		for _, p := range r.empty {
+			if _, ok := chainLibs[chain][p]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	aliveChains := map[int]bool{}
+	for i := range chains {
+		aliveChains[i] = true
+	}
+	liveRegions := map[*bensonRegion]bool{}
+	for _, r := range regions {
+		liveRegions[r] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		// Drop chains with fewer than two vital regions.
+		for c := range aliveChains {
+			count := 0
+			for r := range liveRegions {
+				if vital(r, c) {
+					count++
+				}
+			}
+			if count < 2 {
+				delete(aliveChains, c)
+				changed = true
+			}
+		}
+		// Drop regions bordered by a chain that is no longer alive.
+		for r := range liveRegions {
+			for c := range r.chains {
+				if !aliveChains[c] {
+					delete(liveRegions, r)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var alive [][]engine.Position
+	for i, chain := range chains {
+		if aliveChains[i] {
+			alive = append(alive, chain)
+		}
+	}
+	var territory []engine.Position
+	for _, r := range regions {
+		if !liveRegions[r] || len(r.chains) == 0 {
+			continue
+		}
+		enclosed := true
+		for _, p := range r.empty {
+			touches := false
+			for c := range r.chains {
+				if _, ok := chainLibs[c][p]; ok {
+					touches = true
+					break
+				}
+			}
+			if !touches {
+				enclosed = false
+				break
+			}
+		}
+		if enclosed {
+			territory = append(territory, r.points...)
+		}
+	}
+	return alive, territory
+}
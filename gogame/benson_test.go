@@ -0,0 +1,72 @@
+package gogame
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPassAliveTwoEyes(t *testing.T) {
+	g := diagram(t,
+		". X O . X",
+		"X X X X X",
+		". . . . .",
+		"O O O O O",
+		". O . O .",
+	)
+	res := PassAlive(g)
+	if len(res.Chains[Black]) != 1 || len(res.Chains[White]) != 1 {
+		t.Fatalf("expected one pass-alive chain per color, got %d black, %d white", len(res.Chains[Black]), len(res.Chains[White]))
+	}
+	for _, c := range []string{"A5", "C5", "D5"} {
+		if owner := res.Owner(coord(t, g, c)); owner != Black {
+			t.Fatalf("expected %s to be Black's pass-alive territory, got %s", c, owner)
+		}
+	}
+	if owner := res.Owner(coord(t, g, "C3")); owner != None {
+		t.Fatalf("expected the open middle row to be unsettled, got %s", owner)
+	}
+
+	score := ScoreArea(g, 0.5)
+	if len(score.Dead) != 1 || score.Dead[0] != coord(t, g, "C5") {
+		t.Fatalf("expected the White stone on C5 to be dead, got %v", score.Dead)
+	}
+	// Black: 7 stones + 3 territory; White: 7 stones + 3 eyes + komi.
+	if score.Black != 10 || score.White != 10.5 || score.Result() != "W+0.5" {
+		t.Fatalf("unexpected score %v-%v (%s)", score.Black, score.White, score.Result())
+	}
+}
+
+func TestOneEyeIsNotPassAlive(t *testing.T) {
+	g := diagram(t,
+		". X . . .",
+		"X X . . .",
+		". . . . .",
+		". . . . .",
+		". . . . .",
+	)
+	if chains := PassAlive(g).Chains[Black]; len(chains) != 0 {
+		t.Fatalf("expected no pass-alive chains, got %v", chains)
+	}
+}
+
+func TestPlayoutStopsWhenSettled(t *testing.T) {
+	// Playouts only check once fewer than 1/8 of the points are empty.
+	g := diagram(t,
+		". X . X X X X",
+		"X X X X X X X",
+		"X X X X X X X",
+		"X X X X X X X",
+		"O O O O O O O",
+		"O O O O O O O",
+		". O . O O O O",
+	)
+	if played := Playout(g, rand.New(rand.NewSource(1)), 100); played != 0 {
+		t.Fatalf("expected a settled board to end the playout immediately, played %d", played)
+	}
+
+	g, _ = NewGame(5)
+	played := Playout(g.Clone(), rand.New(rand.NewSource(1)), 50)
+	if played == 0 || g.MoveNumber() != 0 {
+		t.Fatalf("expected the playout to run on the clone only, played %d, original moves %d", played, g.MoveNumber())
+	}
+}
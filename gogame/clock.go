@@ -0,0 +1,237 @@
+package gogame
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeSystem selects how a player's clock is replenished once moves are made.
+type TimeSystem int
+
+const (
+	// AbsoluteTime gives each player a single budget for the whole game.
+	AbsoluteTime TimeSystem = iota
+	// FischerTime adds Increment to the main time after every move.
+	FischerTime
+	// CanadianTime requires Stones moves in each overtime Period once main time runs out.
+	CanadianTime
+	// ByoYomi (Japanese) grants Periods overtime periods; finishing a move within
+	// a period keeps it, overrunning one uses it up.
+	ByoYomi
+)
+
+func (s TimeSystem) String() string {
+	switch s {
+	case FischerTime:
+		return "fischer"
+	case CanadianTime:
+		return "canadian"
+	case ByoYomi:
+		return "byoyomi"
+	default:
+		return "absolute"
+	}
+}
+
+// ParseTimeSystem converts a name such as "fischer" or "byoyomi" into a TimeSystem.
+func ParseTimeSystem(name string) (TimeSystem, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "absolute":
+		return AbsoluteTime, nil
+	case "fischer":
+		return FischerTime, nil
+	case "canadian":
+		return CanadianTime, nil
+	case "byoyomi", "byo-yomi", "japanese":
+		return ByoYomi, nil
+	default:
+		return AbsoluteTime, fmt.Errorf("unknown time system %q", name)
+	}
+}
+
+// TimeControl describes the time allowed to each player.
+type TimeControl struct {
+	System    TimeSystem
+	MainTime  time.Duration
+	Increment time.Duration // Fischer only
+	Period    time.Duration // length of a Canadian or byo-yomi overtime period
+	Stones    int           // moves required per Canadian period
+	Periods   int           // number of byo-yomi periods
+}
+
+// Overtime describes the overtime rules in SGF OT[] style, or "" when there are none.
+func (tc TimeControl) Overtime() string {
+	switch tc.System {
+	case FischerTime:
+		return fmt.Sprintf("Fischer %s increment", tc.Increment)
+	case CanadianTime:
+		return fmt.Sprintf("%d/%s Canadian", tc.Stones, tc.Period)
+	case ByoYomi:
+		return fmt.Sprintf("%dx%s byo-yomi", tc.Periods, tc.Period)
+	default:
+		return ""
+	}
+}
+
+// PlayerClock is a snapshot of one player's remaining time.
+type PlayerClock struct {
+	Main        time.Duration // main time left
+	Overtime    bool          // main time is exhausted and overtime has begun
+	Period      time.Duration // time left in the current overtime period
+	PeriodsLeft int           // byo-yomi periods remaining
+	StonesLeft  int           // Canadian moves still due in the current period
+	Flagged     bool          // the player has run out of time
+}
+
+// Left returns the time available before the player flags within the current
+// period, which is what SGF BL/WL record.
+func (pc PlayerClock) Left() time.Duration {
+	if pc.Overtime {
+		return pc.Period
+	}
+	return pc.Main
+}
+
+// Clock runs a game clock for both colors. Now is injectable so tests can
+// drive time explicitly; it defaults to time.Now.
+type Clock struct {
+	Control TimeControl
+	Now     func() time.Time
+	players map[Color]*PlayerClock
+	running Color
+	started time.Time
+}
+
+// NewClock creates a stopped clock with full time for both players.
+func NewClock(tc TimeControl, now func() time.Time) *Clock {
+	if now == nil {
+		now = time.Now
+	}
+	c := &Clock{Control: tc, Now: now, players: map[Color]*PlayerClock{}}
+	for _, color := range []Color{Black, White} {
+		c.players[color] = &PlayerClock{Main: tc.MainTime}
+	}
+	return c
+}
+
+// Start begins counting time for the given color.
+func (c *Clock) Start(color Color) {
+	c.running = color
+	c.started = c.Now()
+}
+
+// Stop halts the clock, charging the running player for the time used so far.
+func (c *Clock) Stop() {
+	if c.running == None {
+		return
+	}
+	c.charge(c.players[c.running], c.Now().Sub(c.started), false)
+	c.running = None
+}
+
+// Running returns the color whose clock is ticking, or None.
+func (c *Clock) Running() Color {
+	return c.running
+}
+
+// Press ends the running player's turn: their elapsed time is charged, any
+// increment or overtime refill applied, and the opponent's clock started.
+// It reports false when the player had already run out of time.
+func (c *Clock) Press() bool {
+	color := c.running
+	if color == None {
+		return true
+	}
+	now := c.Now()
+	pc := c.players[color]
+	ok := c.charge(pc, now.Sub(c.started), true)
+	c.running = other(color)
+	c.started = now
+	return ok
+}
+
+// Remaining returns a snapshot of a player's clock including time used on the current move.
+func (c *Clock) Remaining(color Color) PlayerClock {
+	pc, ok := c.players[color]
+	if !ok {
+		return PlayerClock{}
+	}
+	snapshot := *pc
+	if color == c.running {
+		c.charge(&snapshot, c.Now().Sub(c.started), false)
+	}
+	return snapshot
+}
+
+// Flagged reports whether a player has run out of time.
+func (c *Clock) Flagged(color Color) bool {
+	return c.Remaining(color).Flagged
+}
+
+// charge deducts elapsed time from a player's clock. When moved is true the
+// player completed a move, so increments and overtime periods are refreshed.
+// It reports false if the player ran out of time.
+func (c *Clock) charge(pc *PlayerClock, elapsed time.Duration, moved bool) bool {
+	if pc.Flagged {
+		return false
+	}
+	tc := c.Control
+	if !pc.Overtime {
+		if elapsed <= pc.Main {
+			pc.Main -= elapsed
+			if moved && tc.System == FischerTime {
+				pc.Main += tc.Increment
+			}
+			return true
+		}
+		elapsed -= pc.Main
+		pc.Main = 0
+		switch tc.System {
+		case CanadianTime:
+			pc.Overtime = true
+			pc.Period = tc.Period
+			pc.StonesLeft = tc.Stones
+		case ByoYomi:
+			pc.Overtime = true
+			pc.Period = tc.Period
+			pc.PeriodsLeft = tc.Periods
+		default:
+			pc.Flagged = true
+			return false
+		}
+	}
+
+	switch tc.System {
+	case CanadianTime:
+		if elapsed > pc.Period {
+			pc.Period = 0
+			pc.Flagged = true
+			return false
+		}
+		pc.Period -= elapsed
+		if moved {
+			pc.StonesLeft--
+			if pc.StonesLeft <= 0 {
+				pc.Period = tc.Period
+				pc.StonesLeft = tc.Stones
+			}
+		}
+	case ByoYomi:
+		for elapsed > pc.Period {
+			elapsed -= pc.Period
+			pc.PeriodsLeft--
+			pc.Period = tc.Period
+			if pc.PeriodsLeft <= 0 {
+				pc.Period = 0
+				pc.Flagged = true
+				return false
+			}
+		}
+		pc.Period -= elapsed
+		if moved {
+			pc.Period = tc.Period
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package gogame
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeNow is a manually advanced clock source.
+type fakeNow struct{ t time.Time }
+
+func (f *fakeNow) Now() time.Time          { return f.t }
+func (f *fakeNow) Advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func TestFischerIncrement(t *testing.T) {
+	now := &fakeNow{t: time.Unix(0, 0)}
+	c := NewClock(TimeControl{System: FischerTime, MainTime: time.Minute, Increment: 10 * time.Second}, now.Now)
+	c.Start(Black)
+	now.Advance(15 * time.Second)
+	if !c.Press() {
+		t.Fatalf("Black should still have time")
+	}
+	if got := c.Remaining(Black).Main; got != 55*time.Second {
+		t.Fatalf("expected 55s after increment, got %s", got)
+	}
+	if c.Running() != White {
+		t.Fatalf("expected White's clock to run")
+	}
+}
+
+func TestByoYomiPeriods(t *testing.T) {
+	now := &fakeNow{t: time.Unix(0, 0)}
+	c := NewClock(TimeControl{System: ByoYomi, MainTime: 10 * time.Second, Period: 5 * time.Second, Periods: 2}, now.Now)
+	c.Start(Black)
+	// Uses main time and one full period, finishing inside the second.
+	now.Advance(17 * time.Second)
+	if !c.Press() {
+		t.Fatalf("Black should survive on the last period")
+	}
+	pc := c.Remaining(Black)
+	if !pc.Overtime || pc.PeriodsLeft != 1 || pc.Period != 5*time.Second {
+		t.Fatalf("unexpected byo-yomi state: %+v", pc)
+	}
+	now.Advance(time.Second)
+	c.Press()
+	now.Advance(6 * time.Second)
+	if !c.Flagged(Black) {
+		t.Fatalf("expected Black to flag after overrunning the last period")
+	}
+}
+
+func TestCanadianStonesResetPeriod(t *testing.T) {
+	now := &fakeNow{t: time.Unix(0, 0)}
+	c := NewClock(TimeControl{System: CanadianTime, Period: 10 * time.Second, Stones: 2}, now.Now)
+	c.Start(Black)
+	for i := 0; i < 2; i++ {
+		now.Advance(4 * time.Second)
+		if !c.Press() {
+			t.Fatalf("move %d: Black should have time", i)
+		}
+		c.Press() // White replies instantly
+	}
+	pc := c.Remaining(Black)
+	if pc.StonesLeft != 2 || pc.Period != 10*time.Second {
+		t.Fatalf("expected a fresh period after two stones, got %+v", pc)
+	}
+}
+
+func TestTimeoutAndResignResults(t *testing.T) {
+	now := &fakeNow{t: time.Unix(0, 0)}
+	g, err := NewGame(9)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	g.StartClock(TimeControl{MainTime: time.Minute}, now.Now)
+	play(t, g, "E5")
+	now.Advance(2 * time.Minute)
+	pos, _ := ParseCoord("C3", g.Size)
+	if _, err := g.PlayMove(pos); err == nil {
+		t.Fatalf("expected White's move to be rejected after flagging")
+	}
+	if winner, over := g.Status(); !over || winner != Black || g.Result != "B+T" {
+		t.Fatalf("expected B+T, got winner=%s over=%v result=%q", winner, over, g.Result)
+	}
+	sgf := WriteSGF(g)
+	for _, want := range []string{"RE[B+T]", "BL[60]", "WL[0]"} {
+		if !strings.Contains(sgf, want) {
+			t.Fatalf("expected %s in %s", want, sgf)
+		}
+	}
+
+	g, _ = NewGame(9)
+	if err := g.Resign(Black); err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	if g.Winner != White || g.Result != "W+R" {
+		t.Fatalf("expected W+R, got %s %q", g.Winner, g.Result)
+	}
+
+	// Two passes end the game before it is scored, so neither side can
+	// resign or lose on time afterwards.
+	g, _ = NewGame(9)
+	play(t, g, "pass")
+	play(t, g, "pass")
+	if err := g.Resign(White); err == nil {
+		t.Fatal("expected resigning after two passes to be refused")
+	}
+	if err := g.TimeOut(Black); err == nil {
+		t.Fatal("expected a timeout after two passes to be refused")
+	}
+	if g.Winner != None || g.Result != "" {
+		t.Fatalf("expected the result to be left for scoring, got %s %q", g.Winner, g.Result)
+	}
+}
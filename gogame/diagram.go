@@ -0,0 +1,312 @@
+package gogame
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// defaultCellSize is the distance between lines in rendered diagrams, in
+// pixels, when RenderOptions does not give one.
+const defaultCellSize = 30
+
+// Theme is the palette of a rendered diagram.
+type Theme struct {
+	Board color.RGBA // background
+	Line  color.RGBA // grid, star points, coordinates, and marks on empty points
+	Black color.RGBA // black stones
+	White color.RGBA // white stones
+}
+
+// Built-in themes. ClassicTheme is used when RenderOptions has none.
+var (
+	ClassicTheme = Theme{
+		Board: color.RGBA{0xdc, 0xb3, 0x5c, 0xff},
+		Line:  color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Black: color.RGBA{0x00, 0x00, 0x00, 0xff},
+		White: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+	// PrintTheme draws on white, for problem books and printed reports.
+	PrintTheme = Theme{
+		Board: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Line:  color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Black: color.RGBA{0x00, 0x00, 0x00, 0xff},
+		White: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+	DarkTheme = Theme{
+		Board: color.RGBA{0x2b, 0x2b, 0x2b, 0xff},
+		Line:  color.RGBA{0xb0, 0xb0, 0xb0, 0xff},
+		Black: color.RGBA{0x10, 0x10, 0x10, 0xff},
+		White: color.RGBA{0xe8, 0xe8, 0xe8, 0xff},
+	}
+)
+
+// ParseTheme returns the built-in theme called name: "classic" (the
+// default), "print", or "dark".
+func ParseTheme(name string) (Theme, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "classic":
+		return ClassicTheme, nil
+	case "print":
+		return PrintTheme, nil
+	case "dark":
+		return DarkTheme, nil
+	default:
+		return ClassicTheme, fmt.Errorf("unknown theme %q (want classic, print, or dark)", name)
+	}
+}
+
+// Markup is SGF board markup drawn over a diagram: triangles (TR),
+// squares (SQ), and text labels (LB).
+type Markup struct {
+	Triangles []engine.Position
+	Squares   []engine.Position
+	Labels    map[engine.Position]string
+}
+
+// ParseMarkup reads the TR, SQ, and LB properties of an SGF node on a board
+// of the given dimensions.
+func ParseMarkup(n *SGFNode, rows, cols int) (Markup, error) {
+	var m Markup
+	for _, prop := range []struct {
+		name string
+		dst  *[]engine.Position
+	}{{"TR", &m.Triangles}, {"SQ", &m.Squares}} {
+		for _, v := range n.Props[prop.name] {
+			points, err := sgfPoints(v, rows, cols)
+			if err != nil {
+				return Markup{}, fmt.Errorf("%s: %w", prop.name, err)
+			}
+			*prop.dst = append(*prop.dst, points...)
+		}
+	}
+	for _, v := range n.Props["LB"] {
+		point, text, ok := strings.Cut(v, ":")
+		if !ok {
+			return Markup{}, fmt.Errorf("LB: label %q has no text", v)
+		}
+		pos, err := ParseSGFCoord(point, rows, cols)
+		if err != nil {
+			return Markup{}, fmt.Errorf("LB: %w", err)
+		}
+		if m.Labels == nil {
+			m.Labels = map[engine.Position]string{}
+		}
+		m.Labels[pos] = text
+	}
+	return m, nil
+}
+
+// RenderOptions configures RenderSVG and RenderPNG. The zero value draws a
+// classic wooden board with coordinates and a ring on the last move.
+type RenderOptions struct {
+	CellSize        int   // pixels between lines; defaults to 30
+	Theme           Theme // defaults to ClassicTheme
+	HideCoordinates bool
+	HideLastMove    bool
+	// NumberFrom numbers the stones still on the board that were played at
+	// or after this move; 0 numbers none. The last move's ring is left off
+	// when its stone is numbered.
+	NumberFrom int
+	Markup     Markup
+}
+
+// shapeKind is one kind of drawing primitive in a diagram.
+type shapeKind int
+
+const (
+	shapeLine     shapeKind = iota // x, y to x2, y2, size wide
+	shapeDisc                      // filled circle of radius size
+	shapeRing                      // circle outline of radius size, width wide
+	shapeTriangle                  // outline of an upward triangle of circumradius size
+	shapeSquare                    // outline of a square of half-side size
+	shapeText                      // text centered on x, y with font size size
+)
+
+// shape is a drawing primitive in pixels. SVG and PNG output draw the same
+// shapes, so the two formats match.
+type shape struct {
+	kind   shapeKind
+	x, y   float64
+	x2, y2 float64
+	size   float64
+	width  float64
+	color  color.RGBA
+	text   string
+}
+
+// boardDiagram is a laid-out board: its size in pixels, background, and shapes
+// in drawing order.
+type boardDiagram struct {
+	width, height int
+	background    color.RGBA
+	shapes        []shape
+}
+
+// layout places everything RenderOptions asks for on the position in g.
+func layout(g *Game, o RenderOptions) boardDiagram {
+	cell := float64(o.CellSize)
+	if o.CellSize <= 0 {
+		cell = defaultCellSize
+	}
+	theme := o.Theme
+	if theme == (Theme{}) {
+		theme = ClassicTheme
+	}
+	margin := cell / 2
+	if !o.HideCoordinates {
+		margin = cell
+	}
+	d := boardDiagram{
+		width:      int(math.Round(2*margin + float64(g.Cols-1)*cell)),
+		height:     int(math.Round(2*margin + float64(g.Rows-1)*cell)),
+		background: theme.Board,
+	}
+	line := math.Max(1, math.Round(cell/30))
+	// Lines an odd number of pixels wide sit on pixel centers so that
+	// they are drawn crisply.
+	offset := math.Mod(line, 2) / 2
+	x := func(col int) float64 { return margin + offset + float64(col)*cell }
+	y := func(row int) float64 { return margin + offset + float64(row)*cell }
+	add := func(s shape) { d.shapes = append(d.shapes, s) }
+
+	for r := 0; r < g.Rows; r++ {
+		add(shape{kind: shapeLine, x: x(0), y: y(r), x2: x(g.Cols - 1), y2: y(r), size: line, color: theme.Line})
+	}
+	for c := 0; c < g.Cols; c++ {
+		add(shape{kind: shapeLine, x: x(c), y: y(0), x2: x(c), y2: y(g.Rows - 1), size: line, color: theme.Line})
+	}
+	for _, p := range StarPoints(g.Rows, g.Cols) {
+		add(shape{kind: shapeDisc, x: x(p.Col), y: y(p.Row), size: cell / 10, color: theme.Line})
+	}
+	if !o.HideCoordinates {
+		far := func(n int) float64 { return 2*margin + offset + float64(n-1)*cell - margin/2 }
+		for c, label := range ColumnLabels(g.Cols) {
+			add(shape{kind: shapeText, x: x(c), y: margin / 2, size: cell * 0.4, color: theme.Line, text: label})
+			add(shape{kind: shapeText, x: x(c), y: far(g.Rows), size: cell * 0.4, color: theme.Line, text: label})
+		}
+		for r := 0; r < g.Rows; r++ {
+			label := strconv.Itoa(g.Rows - r)
+			add(shape{kind: shapeText, x: margin / 2, y: y(r), size: cell * 0.4, color: theme.Line, text: label})
+			add(shape{kind: shapeText, x: far(g.Cols), y: y(r), size: cell * 0.4, color: theme.Line, text: label})
+		}
+	}
+
+	// ink returns the color that stands out on the point at pos.
+	ink := func(pos engine.Position) color.RGBA {
+		switch stoneAt(g, pos) {
+		case Black:
+			return theme.White
+		case White:
+			return theme.Black
+		}
+		return theme.Line
+	}
+	radius := cell * 0.47
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		switch Color(v) {
+		case Black:
+			add(shape{kind: shapeDisc, x: x(pos.Col), y: y(pos.Row), size: radius, color: theme.Black})
+		case White:
+			add(shape{kind: shapeDisc, x: x(pos.Col), y: y(pos.Row), size: radius, color: theme.Line})
+			add(shape{kind: shapeDisc, x: x(pos.Col), y: y(pos.Row), size: radius - line, color: theme.White})
+		}
+	})
+
+	numbers := moveNumbers(g, o.NumberFrom)
+	for _, pos := range sortedPositions(numbers) {
+		text := strconv.Itoa(numbers[pos])
+		size := cell * 0.5
+		if len(text) > 2 {
+			size = cell * 0.38
+		}
+		add(shape{kind: shapeText, x: x(pos.Col), y: y(pos.Row), size: size, color: ink(pos), text: text})
+	}
+	if rec, ok := g.LastMove(); ok && !rec.Pass && !o.HideLastMove {
+		if _, numbered := numbers[rec.Pos]; !numbered && stoneAt(g, rec.Pos) != None {
+			add(shape{kind: shapeRing, x: x(rec.Pos.Col), y: y(rec.Pos.Row), size: radius / 2, width: 2 * line, color: ink(rec.Pos)})
+		}
+	}
+
+	for _, pos := range o.Markup.Triangles {
+		add(shape{kind: shapeTriangle, x: x(pos.Col), y: y(pos.Row), size: cell * 0.3, width: 2 * line, color: ink(pos)})
+	}
+	for _, pos := range o.Markup.Squares {
+		add(shape{kind: shapeSquare, x: x(pos.Col), y: y(pos.Row), size: cell * 0.22, width: 2 * line, color: ink(pos)})
+	}
+	for _, pos := range sortedPositions(o.Markup.Labels) {
+		if stoneAt(g, pos) == None {
+			// Clear the grid under the label so that it can be read.
+			add(shape{kind: shapeDisc, x: x(pos.Col), y: y(pos.Row), size: cell * 0.4, color: theme.Board})
+		}
+		add(shape{kind: shapeText, x: x(pos.Col), y: y(pos.Row), size: cell * 0.5, color: ink(pos), text: o.Markup.Labels[pos]})
+	}
+	return d
+}
+
+// moveNumbers maps each point to the number of the move that placed its
+// stone, for stones still on the board that were played at or after move
+// from. It is empty when from is 0.
+func moveNumbers(g *Game, from int) map[engine.Position]int {
+	numbers := map[engine.Position]int{}
+	if from <= 0 {
+		return numbers
+	}
+	for i, rec := range g.Moves() {
+		for _, p := range rec.Captured {
+			delete(numbers, p)
+		}
+		if !rec.Pass && i+1 >= from {
+			numbers[rec.Pos] = i + 1
+		}
+	}
+	for pos := range numbers {
+		if stoneAt(g, pos) == None {
+			delete(numbers, pos)
+		}
+	}
+	return numbers
+}
+
+// stoneAt returns the color of the stone at pos, or None.
+func stoneAt(g *Game, pos engine.Position) Color {
+	v, _ := g.Board.Get(pos)
+	return Color(v)
+}
+
+// StarPoints returns the traditional star points (hoshi) for a board: the
+// points three lines in from each corner (two on boards under 12 lines),
+// the center of boards with an odd number of lines, and the side midpoints
+// of boards of 15 lines or more. Boards under 7 lines have none.
+func StarPoints(rows, cols int) []engine.Position {
+	if rows < 7 || cols < 7 {
+		return nil
+	}
+	inset := func(n int) int {
+		if n >= 12 {
+			return 3
+		}
+		return 2
+	}
+	rowLines := []int{inset(rows), rows - 1 - inset(rows)}
+	colLines := []int{inset(cols), cols - 1 - inset(cols)}
+	odd := rows%2 == 1 && cols%2 == 1
+	if odd && rows >= 15 && cols >= 15 {
+		rowLines = append(rowLines, rows/2)
+		colLines = append(colLines, cols/2)
+	}
+	var out []engine.Position
+	for _, r := range rowLines {
+		for _, c := range colLines {
+			out = append(out, engine.Position{Row: r, Col: c})
+		}
+	}
+	if odd && (rows < 15 || cols < 15) {
+		out = append(out, engine.Position{Row: rows / 2, Col: cols / 2})
+	}
+	return out
+}
@@ -8,28 +8,42 @@ import (
 	"boardgame/engine"
 )
 
+// labelLetters is the Go coordinate alphabet, which skips I.
+const labelLetters = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
 // ColumnLabels returns board column labels (skipping I like traditional Go coordinates).
+// Boards wider than 25 columns continue with double letters: AA, AB, ...
 func ColumnLabels(size int) []string {
+	n := len(labelLetters)
 	labels := make([]string, 0, size)
-	for ch := 'A'; len(labels) < size; ch++ {
-		if ch == 'I' { // skip I
+	for i := 0; i < size; i++ {
+		if i < n {
+			labels = append(labels, labelLetters[i:i+1])
 			continue
 		}
-		labels = append(labels, string(ch))
+		j := i - n
+		labels = append(labels, string([]byte{labelLetters[j/n%n], labelLetters[j%n]}))
 	}
 	return labels
 }
 
-// ParseCoord converts user text (e.g., "D4") into a board position.
+// ParseCoord converts user text (e.g., "D4") into a board position on a square board.
 // Rows count from the bottom (1) upwards to the board size.
 func ParseCoord(input string, size int) (engine.Position, error) {
+	return ParseCoordRect(input, size, size)
+}
+
+// ParseCoordRect converts user text (e.g., "D4" or "AB30") into a position on a board
+// with the given rows and columns.
+func ParseCoordRect(input string, rows, cols int) (engine.Position, error) {
 	s := strings.TrimSpace(strings.ToUpper(input))
-	if len(s) < 2 {
+	split := strings.IndexFunc(s, func(r rune) bool { return r < 'A' || r > 'Z' })
+	if len(s) < 2 || split <= 0 {
 		return engine.Position{}, fmt.Errorf("coordinate must include column and row (e.g., D4)")
 	}
 
-	labels := ColumnLabels(size)
-	colLabel := s[:1]
+	labels := ColumnLabels(cols)
+	colLabel := s[:split]
 	col := -1
 	for i, l := range labels {
 		if l == colLabel {
@@ -41,40 +55,55 @@ func ParseCoord(input string, size int) (engine.Position, error) {
 		return engine.Position{}, fmt.Errorf("invalid column %q", colLabel)
 	}
 
-	rowNum, err := strconv.Atoi(s[1:])
+	rowNum, err := strconv.Atoi(s[split:])
 	if err != nil {
 		return engine.Position{}, fmt.Errorf("invalid row number")
 	}
-	if rowNum < 1 || rowNum > size {
-		return engine.Position{}, fmt.Errorf("row must be between 1 and %d", size)
+	if rowNum < 1 || rowNum > rows {
+		return engine.Position{}, fmt.Errorf("row must be between 1 and %d", rows)
 	}
 
 	// Row index 0 is the top, so invert from bottom-based numbering.
-	row := size - rowNum
+	row := rows - rowNum
 	return engine.Position{Row: row, Col: col}, nil
 }
 
+// FormatCoord is the inverse of ParseCoordRect, e.g. "D4".
+func FormatCoord(pos engine.Position, rows, cols int) string {
+	labels := ColumnLabels(cols)
+	if pos.Col < 0 || pos.Col >= cols || pos.Row < 0 || pos.Row >= rows {
+		return "?"
+	}
+	return fmt.Sprintf("%s%d", labels[pos.Col], rows-pos.Row)
+}
+
 // RenderBoardASCII prints the board with X (Black), O (White), and . (empty).
 func RenderBoardASCII(g *Game) string {
-	labels := ColumnLabels(g.Size)
+	labels := ColumnLabels(g.Cols)
+	width := len(labels[len(labels)-1])
 	var sb strings.Builder
-	sb.Grow(g.Size * g.Size * 2)
+	sb.Grow((g.Rows + 2) * (g.Cols + 2) * (width + 1))
 
 	writeLabels := func() {
 		sb.WriteString("   ")
 		for _, l := range labels {
-			sb.WriteString(l)
-			sb.WriteByte(' ')
+			sb.WriteString(fmt.Sprintf("%-*s ", width, l))
 		}
 	}
 
 	writeLabels()
 	sb.WriteByte('\n')
 
-	for row := 0; row < g.Size; row++ {
-		displayRow := g.Size - row
-		sb.WriteString(fmt.Sprintf("%2d ", displayRow))
-		for col := 0; col < g.Size; col++ {
+	// The last stone played is bracketed, e.g. "(X)", using the separators around it.
+	last := engine.Position{Row: -1, Col: -1}
+	if rec, ok := g.LastMove(); ok && !rec.Pass {
+		last = rec.Pos
+	}
+
+	for row := 0; row < g.Rows; row++ {
+		displayRow := g.Rows - row
+		sb.WriteString(fmt.Sprintf("%2d", displayRow))
+		for col := 0; col < g.Cols; col++ {
 			val, _ := g.Board.Get(engine.Position{Row: row, Col: col})
 			ch := "."
 			if val == int(Black) {
@@ -82,12 +111,26 @@ func RenderBoardASCII(g *Game) string {
 			} else if val == int(White) {
 				ch = "O"
 			}
-			sb.WriteString(ch)
-			sb.WriteByte(' ')
+			sb.WriteByte(markSeparator(last, row, col))
+			sb.WriteString(fmt.Sprintf("%-*s", width, ch))
 		}
+		sb.WriteByte(markSeparator(last, row, g.Cols))
 		sb.WriteString(fmt.Sprintf("%2d\n", displayRow))
 	}
 
 	writeLabels()
 	return sb.String()
 }
+
+// markSeparator returns the character written before column col: an opening
+// bracket before the last move, a closing one after it, and a space otherwise.
+func markSeparator(last engine.Position, row, col int) byte {
+	switch {
+	case last.Row == row && last.Col == col:
+		return '('
+	case last.Row == row && last.Col == col-1:
+		return ')'
+	default:
+		return ' '
+	}
+}
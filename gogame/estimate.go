@@ -0,0 +1,214 @@
+package gogame
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// Bouzy's 5/21 parameters: dilations spread influence, erosions trim it back
+// so only areas firmly held by one color remain.
+const (
+	bouzyDilations = 5
+	bouzyErosions  = 21
+	bouzyStone     = 128
+	// weakLean caps the ownership of points Bouzy's method leaves neutral.
+	weakLean = 0.45
+)
+
+// Estimate is a territory and influence estimate for a position.
+type Estimate struct {
+	Rows, Cols int
+	// Ownership holds, per point, +1 for certainly Black through -1 for
+	// certainly White, indexed [row][col].
+	Ownership [][]float64
+	// Score is the predicted area-scoring margin for Black after komi;
+	// negative values favour White.
+	Score float64
+}
+
+// At returns the ownership of a point.
+func (e Estimate) At(pos engine.Position) float64 {
+	return e.Ownership[pos.Row][pos.Col]
+}
+
+// Result formats the predicted score as an SGF-style result, e.g. "B+4.5".
+func (e Estimate) Result() string {
+	return Score{Black: e.Score}.Result()
+}
+
+// EstimateOwnership predicts who owns each point using Bouzy's 5/21
+// dilation/erosion model: points left with influence after erosion count fully
+// for that color, and neutral points get a weak lean from the dilated
+// influence. Points settled by Benson's algorithm are fixed at ±1, including
+// dead stones inside pass-alive territory.
+func EstimateOwnership(g *Game, komi float64) Estimate {
+	rows, cols := g.Rows, g.Cols
+	influence := make([][]int, rows)
+	for r := range influence {
+		influence[r] = make([]int, cols)
+	}
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		switch Color(v) {
+		case Black:
+			influence[pos.Row][pos.Col] = bouzyStone
+		case White:
+			influence[pos.Row][pos.Col] = -bouzyStone
+		}
+	})
+	for i := 0; i < bouzyDilations; i++ {
+		influence = dilate(g.Board, influence)
+	}
+	// Influence after dilation only gives a soft lean for points that erosion
+	// leaves neutral.
+	spread := influence
+	maxSpread := 1
+	for _, row := range spread {
+		for _, v := range row {
+			if v > -bouzyStone && v < bouzyStone {
+				maxSpread = max(maxSpread, v, -v)
+			}
+		}
+	}
+	for i := 0; i < bouzyErosions; i++ {
+		influence = erode(g.Board, influence)
+	}
+
+	safe := PassAlive(g)
+	e := Estimate{Rows: rows, Cols: cols, Ownership: make([][]float64, rows)}
+	total := 0.0
+	for r := 0; r < rows; r++ {
+		e.Ownership[r] = make([]float64, cols)
+		for c := 0; c < cols; c++ {
+			pos := engine.Position{Row: r, Col: c}
+			var own float64
+			switch v := influence[r][c]; {
+			case v > 0:
+				own = 1
+			case v < 0:
+				own = -1
+			default:
+				own = weakLean * float64(max(-maxSpread, min(maxSpread, spread[r][c]))) / float64(maxSpread)
+			}
+			switch safe.Owner(pos) {
+			case Black:
+				own = 1
+			case White:
+				own = -1
+			}
+			e.Ownership[r][c] = own
+			total += own
+		}
+	}
+	e.Score = total - komi
+	return e
+}
+
+// dilate adds, for each point not touching opposing influence, the number of
+// neighbors sharing its sign.
+func dilate(b *engine.Board, in [][]int) [][]int {
+	out := copyInfluence(in)
+	b.ForEach(func(pos engine.Position, _ int) {
+		v := in[pos.Row][pos.Col]
+		pos0, neg := 0, 0
+		for _, n := range neighbors(b, pos) {
+			nv := in[n.Row][n.Col]
+			if nv > 0 {
+				pos0++
+			} else if nv < 0 {
+				neg++
+			}
+		}
+		if v >= 0 && neg == 0 {
+			out[pos.Row][pos.Col] += pos0
+		}
+		if v <= 0 && pos0 == 0 {
+			out[pos.Row][pos.Col] -= neg
+		}
+	})
+	return out
+}
+
+// erode subtracts, for each point, the number of neighbors that do not share
+// its sign, never crossing zero.
+func erode(b *engine.Board, in [][]int) [][]int {
+	out := copyInfluence(in)
+	b.ForEach(func(pos engine.Position, _ int) {
+		v := in[pos.Row][pos.Col]
+		if v == 0 {
+			return
+		}
+		loss := 0
+		for _, n := range neighbors(b, pos) {
+			nv := in[n.Row][n.Col]
+			if (v > 0 && nv <= 0) || (v < 0 && nv >= 0) {
+				loss++
+			}
+		}
+		switch {
+		case v > 0:
+			out[pos.Row][pos.Col] = max(0, v-loss)
+		case v < 0:
+			out[pos.Row][pos.Col] = min(0, v+loss)
+		}
+	})
+	return out
+}
+
+func copyInfluence(in [][]int) [][]int {
+	out := make([][]int, len(in))
+	for i := range in {
+		out[i] = append([]int(nil), in[i]...)
+	}
+	return out
+}
+
+// RenderOwnershipASCII draws the board with estimated owners: X and O are
+// stones, x and o are points Black and White are expected to own, "," and "'"
+// lean weakly to Black and White, and ! marks stones expected to be captured.
+func RenderOwnershipASCII(g *Game, e Estimate) string {
+	labels := ColumnLabels(g.Cols)
+	width := len(labels[len(labels)-1])
+	var sb strings.Builder
+
+	writeLabels := func() {
+		sb.WriteString("   ")
+		for _, l := range labels {
+			sb.WriteString(fmt.Sprintf("%-*s ", width, l))
+		}
+	}
+
+	writeLabels()
+	sb.WriteByte('\n')
+	for row := 0; row < g.Rows; row++ {
+		displayRow := g.Rows - row
+		sb.WriteString(fmt.Sprintf("%2d ", displayRow))
+		for col := 0; col < g.Cols; col++ {
+			pos := engine.Position{Row: row, Col: col}
+			val, _ := g.Board.Get(pos)
+			own := e.At(pos)
+			ch := "."
+			switch {
+			case Color(val) == Black && own < -0.5, Color(val) == White && own > 0.5:
+				ch = "!"
+			case Color(val) == Black:
+				ch = "X"
+			case Color(val) == White:
+				ch = "O"
+			case own >= 0.5:
+				ch = "x"
+			case own <= -0.5:
+				ch = "o"
+			case own > 0:
+				ch = ","
+			case own < 0:
+				ch = "'"
+			}
+			sb.WriteString(fmt.Sprintf("%-*s ", width, ch))
+		}
+		sb.WriteString(fmt.Sprintf("%2d\n", displayRow))
+	}
+	writeLabels()
+	return sb.String()
+}
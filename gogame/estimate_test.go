@@ -0,0 +1,33 @@
+package gogame
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateOwnershipWalls(t *testing.T) {
+	g := diagram(t,
+		". . . . . . . . .",
+		". . X . . . O . .",
+		". . . . . . . . .",
+		". . X . . . O . .",
+		". . . . . . . . .",
+		". . X . . . O . .",
+		". . . . . . . . .",
+		". . X . . O . . .",
+		". . . . . . . . .",
+	)
+	e := EstimateOwnership(g, 0)
+	if own := e.At(coord(t, g, "A5")); own < 0.5 {
+		t.Fatalf("expected Black to own A5, got %.2f", own)
+	}
+	if own := e.At(coord(t, g, "J5")); own > -0.5 {
+		t.Fatalf("expected White to own J5, got %.2f", own)
+	}
+	if own := e.At(coord(t, g, "E5")); own <= -0.5 || own >= 0.5 {
+		t.Fatalf("expected the centre to stay contested, got %.2f", own)
+	}
+	if !strings.Contains(RenderOwnershipASCII(g, e), " x x X") {
+		t.Fatalf("expected Black territory in rendering:\n%s", RenderOwnershipASCII(g, e))
+	}
+}
@@ -3,6 +3,7 @@ package gogame
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"boardgame/engine"
 )
@@ -27,6 +28,37 @@ func (c Color) String() string {
 	}
 }
 
+// Variant selects how a game is won.
+type Variant int
+
+const (
+	// Standard ends the game after two consecutive passes.
+	Standard Variant = iota
+	// AtariGo ("capture Go") is won by the first player to capture CaptureGoal stones.
+	AtariGo
+)
+
+func (v Variant) String() string {
+	switch v {
+	case AtariGo:
+		return "atari"
+	default:
+		return "standard"
+	}
+}
+
+// ParseVariant converts a variant name such as "standard" or "atari" into a Variant.
+func ParseVariant(name string) (Variant, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "standard":
+		return Standard, nil
+	case "atari", "atari-go", "capture":
+		return AtariGo, nil
+	default:
+		return Standard, fmt.Errorf("unknown variant %q", name)
+	}
+}
+
 // MoveResult describes the outcome of applying a move.
 type MoveResult struct {
 	Captured int
@@ -35,109 +67,245 @@ type MoveResult struct {
 // Game holds Go state for one board.
 type Game struct {
 	Board             *engine.Board
-	Size              int
+	Rows              int
+	Cols              int
+	Size              int // side length of square boards; 0 when the board is rectangular
 	ToPlay            Color
 	Captures          map[Color]int
 	ConsecutivePasses int
+	Variant           Variant
+	CaptureGoal       int    // stones needed to win Atari Go; 0 means 1
+	Winner            Color  // set once the game is decided by rule, resignation, or time
+	Result            string // SGF-style result such as "B+R" or "W+T"; empty until decided
+	Clock             *Clock // optional game clock; see StartClock
 	moveNumber        int
-	history           map[string]struct{}
+	history           map[string]int // position hash -> times reached, for superko and undo
 	lastHash          string
+	moves             []MoveRecord
+	setup             []Move // stones placed by Setup before the first move
 }
 
-// NewGame initializes an empty board with Black to play.
+// Supported board dimensions. SGF coordinates cannot address more than 52 lines.
+const (
+	MinSize = 2
+	MaxSize = 52
+)
+
+// NewGame initializes an empty square board with Black to play.
 func NewGame(size int) (*Game, error) {
-	if size < 5 {
-		return nil, fmt.Errorf("board size must be at least 5")
+	return NewRectGame(size, size)
+}
+
+// NewRectGame initializes an empty board with the given number of rows and columns.
+func NewRectGame(rows, cols int) (*Game, error) {
+	if rows < MinSize || cols < MinSize || rows > MaxSize || cols > MaxSize {
+		return nil, fmt.Errorf("board dimensions must be between %d and %d", MinSize, MaxSize)
 	}
-	board := engine.NewBoard(size, size)
+	board := engine.NewBoard(rows, cols)
 	g := &Game{
 		Board:    board,
-		Size:     size,
+		Rows:     rows,
+		Cols:     cols,
 		ToPlay:   Black,
 		Captures: map[Color]int{Black: 0, White: 0},
-		history:  map[string]struct{}{},
+		history:  map[string]int{},
+	}
+	if rows == cols {
+		g.Size = rows
 	}
 	hash := serialize(board, g.ToPlay)
 	g.lastHash = hash
-	g.history[hash] = struct{}{}
+	g.history[hash]++
 	return g, nil
 }
 
 // PlayMove places a stone for the current player, enforcing capture, suicide, and simple superko.
 func (g *Game) PlayMove(pos engine.Position) (MoveResult, error) {
+	return g.playMove(pos, true)
+}
+
+// ForcePlay places a stone like PlayMove but skips the superko check. Analysis
+// tools use it to model a side with unlimited ko threats retaking a ko.
+func (g *Game) ForcePlay(pos engine.Position) (MoveResult, error) {
+	return g.playMove(pos, false)
+}
+
+func (g *Game) playMove(pos engine.Position, superko bool) (MoveResult, error) {
 	if g.ToPlay == None {
 		return MoveResult{}, fmt.Errorf("game is finished")
 	}
-	mover := g.ToPlay
-	if pos.Row < 0 || pos.Row >= g.Size || pos.Col < 0 || pos.Col >= g.Size {
-		return MoveResult{}, fmt.Errorf("position out of bounds")
-	}
-
-	working := g.Board.Clone()
-	if err := working.Set(pos, int(g.ToPlay)); err != nil {
+	if err := g.CheckTime(); err != nil {
 		return MoveResult{}, err
 	}
-
-	opponent := other(g.ToPlay)
-	totalCaptured := 0
-
-	for _, n := range neighbors(g.Size, pos) {
-		val, _ := working.Get(n)
-		if Color(val) != opponent {
-			continue
-		}
-		group, libs, err := collectGroup(working, n)
-		if err != nil {
-			return MoveResult{}, err
-		}
-		if len(libs) == 0 {
-			for _, p := range group {
-				if err := working.SetAt(p, 0); err != nil {
-					return MoveResult{}, err
-				}
-			}
-			totalCaptured += len(group)
-		}
+	mover := g.ToPlay
+	if !g.Board.InBounds(pos) {
+		return MoveResult{}, fmt.Errorf("position out of bounds")
 	}
 
-	// Check liberties of the newly placed stone (after any captures).
-	_, libs, err := collectGroup(working, pos)
+	working, captured, err := place(g.Board, mover, pos)
 	if err != nil {
 		return MoveResult{}, err
 	}
-	if len(libs) == 0 {
-		return MoveResult{}, fmt.Errorf("suicide is not allowed")
-	}
-
-	nextToPlay := opponent
+	nextToPlay := other(mover)
 	newHash := serialize(working, nextToPlay)
-	if _, exists := g.history[newHash]; exists {
+	if superko && g.history[newHash] > 0 {
 		return MoveResult{}, fmt.Errorf("move violates superko (repeats a previous position)")
 	}
 
+	totalCaptured := len(captured)
+	g.moves = append(g.moves, MoveRecord{
+		Move:       Move{Color: mover, Pos: pos},
+		Captured:   captured,
+		HashBefore: g.lastHash,
+		HashAfter:  newHash,
+	})
 	g.Board = working
 	g.ToPlay = nextToPlay
 	g.lastHash = newHash
-	g.history[newHash] = struct{}{}
+	g.history[newHash]++
 	g.moveNumber++
 	g.ConsecutivePasses = 0
 	if totalCaptured > 0 {
 		g.Captures[mover] += totalCaptured
+		if g.Variant == AtariGo && g.Captures[mover] >= g.captureGoal() {
+			g.decide(mover, "")
+			return MoveResult{Captured: totalCaptured}, nil
+		}
 	}
+	g.pressClock(mover)
 
 	return MoveResult{Captured: totalCaptured}, nil
 }
 
 // Pass ends the current turn without placing a stone.
 func (g *Game) Pass() {
-	if g.ToPlay == None {
+	if g.ToPlay == None || g.CheckTime() != nil {
 		return
 	}
+	mover := g.ToPlay
+	before := g.lastHash
 	g.moveNumber++
 	g.ConsecutivePasses++
 	g.ToPlay = other(g.ToPlay)
 	g.lastHash = serialize(g.Board, g.ToPlay)
-	g.history[g.lastHash] = struct{}{}
+	g.history[g.lastHash]++
+	g.moves = append(g.moves, MoveRecord{
+		Move:       Move{Color: mover, Pass: true},
+		HashBefore: before,
+		HashAfter:  g.lastHash,
+	})
+	g.pressClock(mover)
+}
+
+// Resign ends the game with the other color winning by resignation. It is
+// refused once the game is over, including after two passes while the
+// result waits for scoring.
+func (g *Game) Resign(c Color) error {
+	if c != Black && c != White {
+		return fmt.Errorf("invalid color %s", c)
+	}
+	if _, over := g.Status(); over {
+		return fmt.Errorf("game is finished")
+	}
+	g.decide(other(c), "R")
+	return nil
+}
+
+// TimeOut ends the game with c losing on time. It is for time kept outside
+// Clock, such as correspondence deadlines.
+func (g *Game) TimeOut(c Color) error {
+	if c != Black && c != White {
+		return fmt.Errorf("invalid color %s", c)
+	}
+	if _, over := g.Status(); over {
+		return fmt.Errorf("game is finished")
+	}
+	g.decide(other(c), "T")
+	return nil
+}
+
+// StartClock attaches a clock with the given time control and starts it for the player to move.
+func (g *Game) StartClock(tc TimeControl, now func() time.Time) *Clock {
+	g.Clock = NewClock(tc, now)
+	if g.ToPlay != None {
+		g.Clock.Start(g.ToPlay)
+	}
+	return g.Clock
+}
+
+// CheckTime ends the game on time if the player to move has run out, returning
+// an error describing the loss. Games without a clock never time out.
+func (g *Game) CheckTime() error {
+	if g.Clock == nil || g.ToPlay == None {
+		return nil
+	}
+	if g.Clock.Flagged(g.ToPlay) {
+		loser := g.ToPlay
+		g.decide(other(loser), "T")
+		return fmt.Errorf("%s ran out of time", loser)
+	}
+	return nil
+}
+
+// pressClock hands the clock to the opponent after a move, ending the game if
+// the mover overran their time while thinking.
+func (g *Game) pressClock(mover Color) {
+	if g.Clock == nil {
+		return
+	}
+	if !g.Clock.Press() {
+		g.decide(other(mover), "T")
+	}
+}
+
+// decide records a winner and stops play. reason is the SGF result suffix,
+// e.g. "R" or "T"; an empty reason records a win without a margin.
+func (g *Game) decide(winner Color, reason string) {
+	g.Winner = winner
+	g.Result = fmt.Sprintf("%s+%s", colorLetter(winner), reason)
+	g.ToPlay = None
+	if g.Clock != nil {
+		g.Clock.Stop()
+	}
+}
+
+// colorLetter returns the SGF letter for a color ("B" or "W").
+func colorLetter(c Color) string {
+	if c == White {
+		return "W"
+	}
+	return "B"
+}
+
+// Status reports whether the game is over and, when the rules decide it, who won.
+// Standard games end after two consecutive passes with no winner set, leaving
+// the result to scoring.
+func (g *Game) Status() (Color, bool) {
+	if g.Winner != None {
+		return g.Winner, true
+	}
+	return None, g.ConsecutivePasses >= 2
+}
+
+func (g *Game) captureGoal() int {
+	if g.CaptureGoal <= 0 {
+		return 1
+	}
+	return g.CaptureGoal
+}
+
+// Hash identifies the current position, including the side to move.
+func (g *Game) Hash() string {
+	return g.lastHash
+}
+
+// ChainAt returns the stones of the chain at pos and its liberties, both in board order.
+func (g *Game) ChainAt(pos engine.Position) ([]engine.Position, []engine.Position, error) {
+	stones, libs, err := collectGroup(g.Board, pos)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sortedPositions(toSet(stones)), sortedPositions(libs), nil
 }
 
 // MoveNumber returns the number of moves played.
@@ -145,6 +313,46 @@ func (g *Game) MoveNumber() int {
 	return g.moveNumber
 }
 
+// place returns a copy of b with a stone of color c added at pos and any
+// captured opponent stones removed. Suicide is rejected.
+func place(b *engine.Board, c Color, pos engine.Position) (*engine.Board, []engine.Position, error) {
+	working := b.Clone()
+	if err := working.Set(pos, int(c)); err != nil {
+		return nil, nil, err
+	}
+
+	opponent := other(c)
+	var captured []engine.Position
+	for _, n := range neighbors(working, pos) {
+		val, _ := working.Get(n)
+		if Color(val) != opponent {
+			continue
+		}
+		group, libs, err := collectGroup(working, n)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(libs) == 0 {
+			for _, p := range group {
+				if err := working.SetAt(p, 0); err != nil {
+					return nil, nil, err
+				}
+			}
+			captured = append(captured, group...)
+		}
+	}
+
+	// Check liberties of the newly placed stone (after any captures).
+	_, libs, err := collectGroup(working, pos)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(libs) == 0 {
+		return nil, nil, fmt.Errorf("suicide is not allowed")
+	}
+	return working, captured, nil
+}
+
 func other(c Color) Color {
 	if c == Black {
 		return White
@@ -155,7 +363,7 @@ func other(c Color) Color {
 	return None
 }
 
-func neighbors(size int, pos engine.Position) []engine.Position {
+func neighbors(b *engine.Board, pos engine.Position) []engine.Position {
 	dirs := []engine.Position{
 		{Row: -1, Col: 0},
 		{Row: 1, Col: 0},
@@ -164,10 +372,9 @@ func neighbors(size int, pos engine.Position) []engine.Position {
 	}
 	out := make([]engine.Position, 0, 4)
 	for _, d := range dirs {
-		r := pos.Row + d.Row
-		c := pos.Col + d.Col
-		if r >= 0 && r < size && c >= 0 && c < size {
-			out = append(out, engine.Position{Row: r, Col: c})
+		n := engine.Position{Row: pos.Row + d.Row, Col: pos.Col + d.Col}
+		if b.InBounds(n) {
+			out = append(out, n)
 		}
 	}
 	return out
@@ -196,7 +403,7 @@ func collectGroup(b *engine.Board, start engine.Position) ([]engine.Position, ma
 		seen[current] = struct{}{}
 		group = append(group, current)
 
-		for _, n := range neighbors(b.Rows, current) {
+		for _, n := range neighbors(b, current) {
 			val, _ := b.Get(n)
 			switch val {
 			case 0:
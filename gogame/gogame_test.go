@@ -1,7 +1,10 @@
 package gogame
 
 import (
+	"strings"
 	"testing"
+
+	"boardgame/engine"
 )
 
 func TestCaptureSingleStone(t *testing.T) {
@@ -10,13 +13,13 @@ func TestCaptureSingleStone(t *testing.T) {
 		t.Fatalf("new game: %v", err)
 	}
 	sequence := []string{
-		"A2", // Black
-		"B2", // White stone to be captured
-		"B1", // Black
-		"C3", // White elsewhere
-		"C2", // Black
-		"C1", // White elsewhere
-		"B3", // Black completes surround; captures B2
+		"A2",   // Black
+		"B2",   // White stone to be captured
+		"B1",   // Black
+		"pass", // White
+		"C2",   // Black
+		"pass", // White
+		"B3",   // Black completes surround; captures B2
 	}
 	for _, coord := range sequence {
 		play(t, g, coord)
@@ -38,13 +41,13 @@ func TestSuicideRejected(t *testing.T) {
 		t.Fatalf("new game: %v", err)
 	}
 	sequence := []string{
-		"A2", // B
-		"C1", // W
-		"B1", // B
-		"C3", // W
-		"B3", // B
-		"A1", // W
-		"C2", // B -> White to play
+		"A2",   // B
+		"pass", // W
+		"B1",   // B
+		"pass", // W
+		"C2",   // B
+		"pass", // W
+		"B3",   // B -> White to play
 	}
 	for _, coord := range sequence {
 		play(t, g, coord)
@@ -57,7 +60,165 @@ func TestSuicideRejected(t *testing.T) {
 	}
 }
 
+func TestAtariGoFirstCaptureWins(t *testing.T) {
+	g, err := NewGame(5)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	g.Variant = AtariGo
+	for _, coord := range []string{"A4", "A5", "B5"} {
+		play(t, g, coord)
+	}
+	winner, over := g.Status()
+	if !over || winner != Black {
+		t.Fatalf("expected Black to win by capture, got winner=%s over=%v", winner, over)
+	}
+	pos, _ := ParseCoord("C3", g.Size)
+	if _, err := g.PlayMove(pos); err == nil {
+		t.Fatalf("expected moves after the winning capture to be rejected")
+	}
+}
+
+func TestRectangularBoard(t *testing.T) {
+	g, err := NewRectGame(7, 9)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	pos, err := ParseCoordRect("J7", g.Rows, g.Cols)
+	if err != nil {
+		t.Fatalf("parse J7: %v", err)
+	}
+	if pos != (engine.Position{Row: 0, Col: 8}) {
+		t.Fatalf("expected top-right corner, got %+v", pos)
+	}
+	if _, err := ParseCoordRect("A8", g.Rows, g.Cols); err == nil {
+		t.Fatalf("expected row 8 to be rejected on a 7-row board")
+	}
+	// Capture in the top-right corner, which only exists if neighbors respect both dimensions.
+	for _, coord := range []string{"H7", "J7", "J6"} {
+		pos, _ := ParseCoordRect(coord, g.Rows, g.Cols)
+		if _, err := g.PlayMove(pos); err != nil {
+			t.Fatalf("play %s: %v", coord, err)
+		}
+	}
+	if g.Captures[Black] != 1 {
+		t.Fatalf("expected Black captures=1, got %d", g.Captures[Black])
+	}
+}
+
+func TestWideColumnLabels(t *testing.T) {
+	labels := ColumnLabels(MaxSize)
+	if labels[24] != "Z" || labels[25] != "AA" || labels[MaxSize-1] != "BB" {
+		t.Fatalf("unexpected labels: %v", labels[23:])
+	}
+	pos, err := ParseCoordRect("AB30", 30, 30)
+	if err != nil {
+		t.Fatalf("parse AB30: %v", err)
+	}
+	if pos != (engine.Position{Row: 0, Col: 26}) {
+		t.Fatalf("expected row 0 col 26, got %+v", pos)
+	}
+	if got := FormatCoord(pos, 30, 30); got != "AB30" {
+		t.Fatalf("expected AB30, got %s", got)
+	}
+}
+
+func TestMoveRecordAndUndo(t *testing.T) {
+	g, err := NewGame(5)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	for _, coord := range []string{"A4", "A5", "B5"} {
+		play(t, g, coord)
+	}
+	rec, ok := g.LastMove()
+	if !ok || rec.Color != Black || len(rec.Captured) != 1 {
+		t.Fatalf("expected Black's capturing move as last move, got %+v", rec)
+	}
+	if got := len(g.Moves()); got != 3 {
+		t.Fatalf("expected 3 recorded moves, got %d", got)
+	}
+	if !strings.Contains(RenderBoardASCII(g), "(X)") {
+		t.Fatalf("expected last move marker in:\n%s", RenderBoardASCII(g))
+	}
+
+	if err := g.Undo(); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	a5, _ := ParseCoord("A5", g.Size)
+	if val, _ := g.Board.Get(a5); Color(val) != White {
+		t.Fatalf("expected captured stone restored at A5, got %d", val)
+	}
+	if g.ToPlay != Black || g.Captures[Black] != 0 || g.MoveNumber() != 2 {
+		t.Fatalf("unexpected state after undo: toPlay=%s captures=%d moves=%d", g.ToPlay, g.Captures[Black], g.MoveNumber())
+	}
+	// The undone position must not count towards superko.
+	play(t, g, "B5")
+
+	replayed, err := Replay(5, 5, []Move{{Pos: rec.Pos}})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !strings.Contains(WriteSGF(replayed), ";B[ba]") {
+		t.Fatalf("expected move node in SGF: %s", WriteSGF(replayed))
+	}
+}
+
+func TestSGFRoundTrip(t *testing.T) {
+	g, _ := NewRectGame(5, 7)
+	if err := g.Setup(White, engine.Position{Row: 2, Col: 2}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	play(t, g, "pass")
+	if _, err := g.PlayMove(engine.Position{Row: 0, Col: 6}); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+	root, err := ParseSGF(WriteSGF(g) + "\n(;C[second \\] tree])")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	loaded, err := GameFromSGF(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Rows != 5 || loaded.Cols != 7 || loaded.Hash() != g.Hash() || loaded.MoveNumber() != 2 {
+		t.Fatalf("loaded game differs:\n%s", RenderBoardASCII(loaded))
+	}
+	if err := g.Resign(Black); err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	root, _ = ParseSGF(WriteSGF(g))
+	if loaded, err = GameFromSGF(root); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Result != "W+R" || loaded.ToPlay != None {
+		t.Fatalf("expected the resignation to be restored, got %q", loaded.Result)
+	}
+
+	root, err = ParseSGF(`(;SZ[3]AB[aa:ab]C[a \] b](;B[cc])(;B[bb]))`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if root.Get("C") != "a ] b" || len(root.Props["AB"]) != 1 || len(root.Children) != 2 {
+		t.Fatalf("unexpected tree: %+v", root)
+	}
+	loaded, err = GameFromSGF(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if v, _ := loaded.Board.Get(engine.Position{Row: 1, Col: 0}); Color(v) != Black {
+		t.Fatalf("expected the compressed AB rectangle to include A2")
+	}
+	if last, _ := loaded.LastMove(); last.Pos != (engine.Position{Row: 2, Col: 2}) {
+		t.Fatalf("expected the main line to follow the first variation, got %v", last.Pos)
+	}
+}
+
 func play(t *testing.T, g *Game, coord string) {
+	if coord == "pass" {
+		g.Pass()
+		return
+	}
 	pos, err := ParseCoord(coord, g.Size)
 	if err != nil {
 		t.Fatalf("parse %s: %v", coord, err)
@@ -0,0 +1,209 @@
+package gogame
+
+import (
+	"fmt"
+	"sort"
+
+	"boardgame/engine"
+)
+
+// maxLadderDepth bounds ladder reading. A ladder gains one stone per pair of
+// moves and crosses the board diagonally, so this is generous even on 52x52.
+const maxLadderDepth = 4 * MaxSize
+
+// LadderResult reports the outcome of reading a ladder.
+type LadderResult struct {
+	// Captured is true when the attacker can capture the chain by repeated ataris.
+	Captured bool
+	// Breaker is the defender's stone the fleeing chain connects to when the
+	// ladder fails. It is nil if the chain escapes by capturing or by gaining
+	// liberties on its own.
+	Breaker *engine.Position
+	// Sequence is the forcing line found, starting with the first move.
+	Sequence []Move
+}
+
+// ReadLadder reads whether the chain at pos can be captured in a ladder.
+// A chain in atari is read with its owner to move (can it run away?); a chain
+// with two liberties is read with the attacker to move (can it be chased down?).
+// Chains with more liberties cannot be laddered. Superko is not considered.
+func ReadLadder(g *Game, pos engine.Position) (LadderResult, error) {
+	val, err := g.Board.Get(pos)
+	if err != nil {
+		return LadderResult{}, err
+	}
+	defender := Color(val)
+	if defender != Black && defender != White {
+		return LadderResult{}, fmt.Errorf("no stone at %s", FormatCoord(pos, g.Rows, g.Cols))
+	}
+	_, libs, err := collectGroup(g.Board, pos)
+	if err != nil {
+		return LadderResult{}, err
+	}
+	r := ladderReader{defender: defender, target: pos}
+	switch len(libs) {
+	case 1:
+		return r.defend(g.Board, 0), nil
+	case 2:
+		return r.attack(g.Board, 0), nil
+	default:
+		return LadderResult{}, fmt.Errorf("chain has %d liberties; ladders need at most 2", len(libs))
+	}
+}
+
+// FutileLadderEscapes returns the liberties of the player-to-move's chains in
+// atari where running away only extends a working ladder. MCTSAgent leaves
+// these moves out of its candidates.
+func FutileLadderEscapes(g *Game) []engine.Position {
+	var out []engine.Position
+	seen := map[engine.Position]bool{}
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		if Color(v) != g.ToPlay || seen[pos] {
+			return
+		}
+		chain, libs, err := collectGroup(g.Board, pos)
+		if err != nil {
+			return
+		}
+		for _, p := range chain {
+			seen[p] = true
+		}
+		if len(libs) != 1 {
+			return
+		}
+		res, err := ReadLadder(g, pos)
+		if err != nil || !res.Captured {
+			return
+		}
+		out = append(out, sortedPositions(libs)...)
+	})
+	return out
+}
+
+type ladderReader struct {
+	defender Color
+	target   engine.Position // any stone of the chain being read
+}
+
+// defend tries every way for the chain in atari to survive: extending at its
+// liberty or capturing an adjacent attacker chain that is itself in atari.
+func (r *ladderReader) defend(b *engine.Board, depth int) LadderResult {
+	chain, libs, _ := collectGroup(b, r.target)
+	candidates := map[engine.Position]bool{}
+	for lib := range libs {
+		candidates[lib] = true
+	}
+	for _, p := range chain {
+		for _, n := range neighbors(b, p) {
+			v, _ := b.Get(n)
+			if Color(v) != other(r.defender) {
+				continue
+			}
+			if _, attLibs, _ := collectGroup(b, n); len(attLibs) == 1 {
+				for lib := range attLibs {
+					candidates[lib] = true
+				}
+			}
+		}
+	}
+
+	var failed LadderResult
+	for _, move := range sortedPositions(candidates) {
+		next, captured, err := place(b, r.defender, move)
+		if err != nil {
+			continue
+		}
+		line := []Move{{Color: r.defender, Pos: move}}
+		newChain, newLibs, _ := collectGroup(next, r.target)
+		switch {
+		case len(newLibs) >= 3:
+			res := LadderResult{Sequence: line}
+			if len(captured) == 0 {
+				res.Breaker = breaker(chain, newChain, move)
+			}
+			return res
+		case len(newLibs) == 2 && depth < maxLadderDepth:
+			res := r.attack(next, depth+1)
+			res.Sequence = append(line, res.Sequence...)
+			if !res.Captured {
+				return res
+			}
+			if len(res.Sequence) > len(failed.Sequence) {
+				failed = res
+			}
+		}
+	}
+	if failed.Sequence == nil {
+		// No useful move: the chain is taken at its last liberty.
+		for _, lib := range sortedPositions(libs) {
+			failed.Sequence = []Move{{Color: other(r.defender), Pos: lib}}
+		}
+	}
+	failed.Captured = true
+	return failed
+}
+
+// attack tries atari at each of the chain's two liberties.
+func (r *ladderReader) attack(b *engine.Board, depth int) LadderResult {
+	_, libs, _ := collectGroup(b, r.target)
+	attacker := other(r.defender)
+	var escaped LadderResult
+	for _, move := range sortedPositions(libs) {
+		next, _, err := place(b, attacker, move)
+		if err != nil {
+			continue
+		}
+		if _, newLibs, _ := collectGroup(next, r.target); len(newLibs) != 1 {
+			continue
+		}
+		res := r.defend(next, depth+1)
+		res.Sequence = append([]Move{{Color: attacker, Pos: move}}, res.Sequence...)
+		if res.Captured {
+			return res
+		}
+		// Report the attacker's most resistant try as the main line.
+		if len(res.Sequence) > len(escaped.Sequence) {
+			escaped = res
+		}
+	}
+	return escaped
+}
+
+// breaker finds a stone that was already on the board and joined the chain
+// on its final extension, which is what makes the ladder fail.
+func breaker(before, after []engine.Position, move engine.Position) *engine.Position {
+	previous := map[engine.Position]bool{move: true}
+	for _, p := range before {
+		previous[p] = true
+	}
+	for _, p := range sortedPositions(toSet(after)) {
+		if !previous[p] {
+			found := p
+			return &found
+		}
+	}
+	return nil
+}
+
+func toSet(list []engine.Position) map[engine.Position]struct{} {
+	set := make(map[engine.Position]struct{}, len(list))
+	for _, p := range list {
+		set[p] = struct{}{}
+	}
+	return set
+}
+
+// sortedPositions returns the keys of a position set in board order so reading is deterministic.
+func sortedPositions[V any](set map[engine.Position]V) []engine.Position {
+	out := make([]engine.Position, 0, len(set))
+	for p := range set {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Row != out[j].Row {
+			return out[i].Row < out[j].Row
+		}
+		return out[i].Col < out[j].Col
+	})
+	return out
+}
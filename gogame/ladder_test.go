@@ -0,0 +1,128 @@
+package gogame
+
+import (
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+)
+
+// The White stone on E5 is in atari and can only run towards the lower left.
+var ladderStart = []string{
+	". . . . . . . . .",
+	". . . . . . . . .",
+	". . . . . . . . .",
+	". . . . X . . . .",
+	". . . X O X . . .",
+	". . . . . X . . .",
+	". . . . . . . . .",
+	". . . . . . . . .",
+	". . . . . . . . .",
+}
+
+func TestLadderWorks(t *testing.T) {
+	g := diagram(t, ladderStart...)
+	if err := g.SetToPlay(White); err != nil {
+		t.Fatalf("set to play: %v", err)
+	}
+	res, err := ReadLadder(g, coord(t, g, "E5"))
+	if err != nil {
+		t.Fatalf("read ladder: %v", err)
+	}
+	if !res.Captured {
+		t.Fatalf("expected ladder to work, got escape via %v", res.Sequence)
+	}
+	if first := res.Sequence[0]; first.Color != White || first.Pos != coord(t, g, "E4") {
+		t.Fatalf("expected White to run at E4 first, got %+v", first)
+	}
+	escapes := FutileLadderEscapes(g)
+	if len(escapes) != 1 || escapes[0] != coord(t, g, "E4") {
+		t.Fatalf("expected E4 to be a futile escape, got %v", escapes)
+	}
+
+	// MCTS leaves the futile escape out of its candidates.
+	root := &mctsNode{}
+	root.expand(g)
+	root.skipFutileEscapes(g)
+	var sawOther bool
+	for _, m := range root.untried {
+		if !m.Pass && m.Pos == coord(t, g, "E4") {
+			t.Fatal("expected MCTS to skip the futile escape at E4")
+		}
+		sawOther = sawOther || m.Pos == coord(t, g, "D4")
+	}
+	if !sawOther {
+		t.Fatal("expected MCTS to keep other moves such as D4")
+	}
+}
+
+func TestLadderBreaker(t *testing.T) {
+	rows := append([]string(nil), ladderStart...)
+	rows[7] = ". O . . . . . . ." // White stone on B2 sits on the ladder's path
+	g := diagram(t, rows...)
+	res, err := ReadLadder(g, coord(t, g, "E5"))
+	if err != nil {
+		t.Fatalf("read ladder: %v", err)
+	}
+	if res.Captured {
+		t.Fatalf("expected the ladder to fail, got capture via %v", res.Sequence)
+	}
+	if res.Breaker == nil || *res.Breaker != coord(t, g, "B2") {
+		t.Fatalf("expected B2 as the breaker, got %v (%v)", res.Breaker, res.Sequence)
+	}
+}
+
+func TestLadderAttackerToMove(t *testing.T) {
+	g := diagram(t,
+		". . . . . . .",
+		". . . . . . .",
+		". . . X . . .",
+		". . X O . . .",
+		". . . . X . .",
+		". . . . . . .",
+		". . . . . . .",
+	)
+	res, err := ReadLadder(g, coord(t, g, "D4"))
+	if err != nil {
+		t.Fatalf("read ladder: %v", err)
+	}
+	if !res.Captured || res.Sequence[0].Color != Black {
+		t.Fatalf("expected Black to start a working ladder, got %+v", res)
+	}
+}
+
+// diagram builds a game from rows of X (Black), O (White), and . (empty), top row first.
+func diagram(t *testing.T, rows ...string) *Game {
+	t.Helper()
+	cols := len(strings.Fields(rows[0]))
+	g, err := NewRectGame(len(rows), cols)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	for r, line := range rows {
+		for c, cell := range strings.Fields(line) {
+			color := None
+			switch cell {
+			case "X":
+				color = Black
+			case "O":
+				color = White
+			default:
+				continue
+			}
+			if err := g.Setup(color, engine.Position{Row: r, Col: c}); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+		}
+	}
+	return g
+}
+
+func coord(t *testing.T, g *Game, text string) engine.Position {
+	t.Helper()
+	pos, err := ParseCoordRect(text, g.Rows, g.Cols)
+	if err != nil {
+		t.Fatalf("parse %s: %v", text, err)
+	}
+	return pos
+}
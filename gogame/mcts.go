@@ -0,0 +1,179 @@
+package gogame
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"boardgame/engine"
+)
+
+// DefaultPlayouts is how many playouts an MCTSAgent runs per move on boards
+// up to 9x9 when Playouts is not set. Playouts on larger boards are longer,
+// so they get proportionally fewer, down to minPlayouts.
+const DefaultPlayouts = 1000
+
+// minPlayouts is the fewest playouts the default gives on a large board.
+const minPlayouts = 100
+
+// uctExploration weighs unexplored moves against moves that have won so far.
+const uctExploration = 0.7
+
+// MCTSAgent chooses moves by Monte Carlo tree search: UCT over the legal
+// moves and pass, with PlayoutAgent rollouts scored by area.
+type MCTSAgent struct {
+	Playouts int     // rollouts per move; 0 means DefaultPlayouts scaled to the board
+	Komi     float64 // used to score rollouts
+	Rand     *rand.Rand
+	// Time stops the search early once it has run this long; 0 means no
+	// limit. At least one playout is always run.
+	Time time.Duration
+}
+
+// defaultPlayouts scales DefaultPlayouts to a board of the given size.
+func defaultPlayouts(rows, cols int) int {
+	return max(minPlayouts, DefaultPlayouts*81/max(rows*cols, 81))
+}
+
+// mctsNode is a position in the search tree, reached by move.
+type mctsNode struct {
+	move     Move
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []Move
+	expanded bool // untried has been filled in
+	visits   int
+	wins     float64 // rollouts won by move.Color through this node
+}
+
+// GenMove implements Agent. It returns the most visited move after the
+// configured number of playouts or time.
+func (a *MCTSAgent) GenMove(g *Game) (Move, error) {
+	if g.ToPlay == None {
+		return Move{}, errors.New("game is finished")
+	}
+	r := a.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(rand.Int63())) //nolint:gosec // best-effort randomness for simulations
+	}
+	playouts := a.Playouts
+	if playouts <= 0 {
+		playouts = defaultPlayouts(g.Rows, g.Cols)
+	}
+	policy := &PlayoutAgent{Rand: r}
+	maxMoves := 3 * g.Rows * g.Cols
+	root := &mctsNode{move: Move{Color: other(g.ToPlay)}}
+	// Rollouts keep only recent superko history, so the root's moves are
+	// listed from the full game to rule out every repeated position.
+	root.expand(g)
+	root.skipFutileEscapes(g)
+
+	start := time.Now()
+	for i := 0; i < playouts; i++ {
+		if a.Time > 0 && i > 0 && time.Since(start) >= a.Time {
+			break
+		}
+		sim := g.rolloutCopy()
+		node := root
+		// Selection: descend through fully expanded nodes.
+		for node.expand(sim) && len(node.untried) == 0 && len(node.children) > 0 {
+			node = node.bestChild()
+			sim.apply(node.move)
+		}
+		// Expansion: add one untried move.
+		if len(node.untried) > 0 {
+			j := r.Intn(len(node.untried))
+			m := node.untried[j]
+			node.untried[j] = node.untried[len(node.untried)-1]
+			node.untried = node.untried[:len(node.untried)-1]
+			child := &mctsNode{move: m, parent: node}
+			node.children = append(node.children, child)
+			node = child
+			sim.apply(m)
+		}
+		PlayoutWith(sim, policy, maxMoves)
+		winner := sim.Winner
+		if winner == None {
+			winner = ScoreArea(sim, a.Komi).Winner()
+		}
+		for n := node; n != nil; n = n.parent {
+			n.visits++
+			if n.move.Color == winner {
+				n.wins++
+			}
+		}
+	}
+
+	var best *mctsNode
+	for _, c := range root.children {
+		if best == nil || c.visits > best.visits {
+			best = c
+		}
+	}
+	if best == nil {
+		return Move{Color: g.ToPlay, Pass: true}, nil
+	}
+	return best.move, nil
+}
+
+// expand lists the node's candidate moves on first visit: every legal move
+// that does not fill the mover's own eye, and pass. Finished games have none.
+// It always returns true so that it can sit in the selection loop condition.
+func (n *mctsNode) expand(g *Game) bool {
+	if n.expanded {
+		return true
+	}
+	n.expanded = true
+	if g.ToPlay == None || g.ConsecutivePasses >= 2 {
+		return true
+	}
+	for _, pos := range LegalMoves(g) {
+		if !IsEye(g.Board, pos, g.ToPlay) {
+			n.untried = append(n.untried, Move{Color: g.ToPlay, Pos: pos})
+		}
+	}
+	n.untried = append(n.untried, Move{Color: g.ToPlay, Pass: true})
+	return true
+}
+
+// skipFutileEscapes drops running moves from chains caught in a working
+// ladder. Only the root is pruned: skipping the opponent's escapes deeper in
+// the tree would hide their replies to atari and make captures look less
+// urgent than they are.
+func (n *mctsNode) skipFutileEscapes(g *Game) {
+	futile := map[engine.Position]bool{}
+	for _, pos := range FutileLadderEscapes(g) {
+		futile[pos] = true
+	}
+	kept := n.untried[:0]
+	for _, m := range n.untried {
+		if m.Pass || !futile[m.Pos] {
+			kept = append(kept, m)
+		}
+	}
+	n.untried = kept
+}
+
+// bestChild picks the child with the highest UCT value.
+func (n *mctsNode) bestChild() *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(float64(n.visits))
+	for _, c := range n.children {
+		v := c.wins/float64(c.visits) + uctExploration*math.Sqrt(logVisits/float64(c.visits))
+		if v > bestValue {
+			best, bestValue = c, v
+		}
+	}
+	return best
+}
+
+// apply plays a search move, which is legal by construction.
+func (g *Game) apply(m Move) {
+	if m.Pass {
+		g.Pass()
+		return
+	}
+	_, _ = g.PlayMove(m.Pos)
+}
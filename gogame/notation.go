@@ -0,0 +1,69 @@
+package gogame
+
+import (
+	"strings"
+
+	"boardgame/engine"
+)
+
+var (
+	_ engine.Notation[*Game, Move] = GTPNotation{}
+	_ engine.Notation[*Game, Move] = SGFNotation{}
+)
+
+// GTPNotation writes moves as GTP vertices: a column letter, skipping I, and
+// a row counted from the bottom, such as "D4", or "pass". Parsed moves are
+// for the side to move; legality is checked when they are played.
+type GTPNotation struct{}
+
+// ParseMove implements engine.Notation.
+func (GTPNotation) ParseMove(g *Game, text string) (Move, error) {
+	m := Move{Color: g.ToPlay}
+	if strings.EqualFold(strings.TrimSpace(text), "pass") {
+		m.Pass = true
+		return m, nil
+	}
+	pos, err := ParseCoordRect(text, g.Rows, g.Cols)
+	if err != nil {
+		return Move{}, err
+	}
+	m.Pos = pos
+	return m, nil
+}
+
+// FormatMove implements engine.Notation.
+func (GTPNotation) FormatMove(g *Game, m Move) string {
+	if m.Pass {
+		return "pass"
+	}
+	return FormatCoord(m.Pos, g.Rows, g.Cols)
+}
+
+// SGFNotation writes moves as SGF points, column then row from the top left,
+// such as "dc". A pass is the empty string; "tt" is also read as a pass on
+// boards up to 19x19, as older SGF files write it.
+type SGFNotation struct{}
+
+// ParseMove implements engine.Notation.
+func (SGFNotation) ParseMove(g *Game, text string) (Move, error) {
+	m := Move{Color: g.ToPlay}
+	text = strings.TrimSpace(text)
+	if text == "" || (text == "tt" && g.Rows <= 19 && g.Cols <= 19) {
+		m.Pass = true
+		return m, nil
+	}
+	pos, err := ParseSGFCoord(text, g.Rows, g.Cols)
+	if err != nil {
+		return Move{}, err
+	}
+	m.Pos = pos
+	return m, nil
+}
+
+// FormatMove implements engine.Notation.
+func (SGFNotation) FormatMove(_ *Game, m Move) string {
+	if m.Pass {
+		return ""
+	}
+	return SGFCoord(m.Pos)
+}
@@ -0,0 +1,42 @@
+package gogame
+
+import (
+	"testing"
+
+	"boardgame/engine"
+)
+
+func TestNotationsRoundTrip(t *testing.T) {
+	g, _ := NewGame(19)
+	cases := []struct {
+		gtp, sgf string
+		move     Move
+	}{
+		{"A19", "aa", Move{Color: Black, Pos: engine.Position{Row: 0, Col: 0}}},
+		{"J10", "ij", Move{Color: Black, Pos: engine.Position{Row: 9, Col: 8}}},
+		{"T1", "ss", Move{Color: Black, Pos: engine.Position{Row: 18, Col: 18}}},
+		{"pass", "", Move{Color: Black, Pass: true}},
+	}
+	for _, c := range cases {
+		if got := (GTPNotation{}).FormatMove(g, c.move); got != c.gtp {
+			t.Errorf("GTP format %+v: got %q, want %q", c.move, got, c.gtp)
+		}
+		if got := (SGFNotation{}).FormatMove(g, c.move); got != c.sgf {
+			t.Errorf("SGF format %+v: got %q, want %q", c.move, got, c.sgf)
+		}
+		if m, err := (GTPNotation{}).ParseMove(g, c.gtp); err != nil || m != c.move {
+			t.Errorf("GTP parse %q: got %+v (%v)", c.gtp, m, err)
+		}
+		if m, err := (SGFNotation{}).ParseMove(g, c.sgf); err != nil || m != c.move {
+			t.Errorf("SGF parse %q: got %+v (%v)", c.sgf, m, err)
+		}
+	}
+	if m, err := (SGFNotation{}).ParseMove(g, "tt"); err != nil || !m.Pass {
+		t.Errorf("expected tt to be a pass on 19x19, got %+v (%v)", m, err)
+	}
+	for _, text := range []string{"I5", "Z1", "A20"} {
+		if _, err := (GTPNotation{}).ParseMove(g, text); err == nil {
+			t.Errorf("expected GTP %q to be rejected", text)
+		}
+	}
+}
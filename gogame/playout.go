@@ -0,0 +1,186 @@
+package gogame
+
+import (
+	"math/rand"
+
+	"boardgame/engine"
+)
+
+// Playouts check whether Benson's algorithm has settled the whole board
+// only once fewer than 1/playoutSettleFraction of the points are empty, and
+// then every playoutSettleInterval moves. A settled board has almost every
+// point filled, and one check costs as much as many moves, so checking
+// earlier slows playouts far more than it shortens them; see
+// BenchmarkPlayoutSettleCheck.
+const (
+	playoutSettleFraction = 8
+	playoutSettleInterval = 8
+)
+
+// Clone returns an independent copy of the game for search and playouts.
+// The clock is not copied.
+func (g *Game) Clone() *Game {
+	c := *g
+	c.Board = g.Board.Clone()
+	c.Clock = nil
+	c.Captures = make(map[Color]int, len(g.Captures))
+	for k, v := range g.Captures {
+		c.Captures[k] = v
+	}
+	c.history = make(map[string]int, len(g.history))
+	for k, v := range g.history {
+		c.history[k] = v
+	}
+	c.moves = append([]MoveRecord(nil), g.moves...)
+	c.setup = append([]Move(nil), g.setup...)
+	return &c
+}
+
+// rolloutCopy returns a copy of g for search and rollouts. Unlike Clone it
+// leaves out the clock, setup, and move list, and keeps only the current
+// position and the one before the last move in the superko history: enough
+// to forbid retaking a ko at once, at a cost that does not grow with the
+// length of the game.
+func (g *Game) rolloutCopy() *Game {
+	c := *g
+	c.Board = g.Board.Clone()
+	c.Clock = nil
+	c.Captures = map[Color]int{Black: g.Captures[Black], White: g.Captures[White]}
+	c.history = map[string]int{g.lastHash: 1}
+	if n := len(g.moves); n > 0 {
+		c.history[g.moves[n-1].HashBefore]++
+	}
+	c.moves = nil
+	c.setup = nil
+	return &c
+}
+
+// LegalMoves lists every point where the player to move may place a stone,
+// honouring suicide and superko rules. Passing is always legal and not included.
+func LegalMoves(g *Game) []engine.Position {
+	if g.ToPlay == None {
+		return nil
+	}
+	var out []engine.Position
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		if v == 0 && g.IsLegal(pos) {
+			out = append(out, pos)
+		}
+	})
+	return out
+}
+
+// IsLegal reports whether the player to move may place a stone at pos.
+func (g *Game) IsLegal(pos engine.Position) bool {
+	if g.ToPlay == None {
+		return false
+	}
+	if hash, done := quietMove(g.Board, g.ToPlay, pos); done {
+		return hash != "" && g.history[hash] == 0
+	}
+	next, _, err := place(g.Board, g.ToPlay, pos)
+	if err != nil {
+		return false
+	}
+	return g.history[serialize(next, other(g.ToPlay))] == 0
+}
+
+// quietMove settles a move by c at pos that captures nothing, which is most
+// moves in a playout, without building the position with place. It reports
+// done with the new position's hash, or with an empty hash when the move is
+// suicide. Captures and occupied points are not done and need place.
+func quietMove(b *engine.Board, c Color, pos engine.Position) (hash string, done bool) {
+	if v, err := b.Get(pos); err != nil || v != 0 {
+		return "", false
+	}
+	breathes := false
+	for _, n := range neighbors(b, pos) {
+		switch v, _ := b.Get(n); Color(v) {
+		case None:
+			breathes = true
+		case c:
+			breathes = breathes || hasLibertyBesides(b, n, pos)
+		default:
+			if !hasLibertyBesides(b, n, pos) {
+				return "", false // a capture
+			}
+		}
+	}
+	if !breathes {
+		return "", true
+	}
+	next := []byte(serialize(b, other(c)))
+	next[1+pos.Row*b.Cols+pos.Col] = byte(c) + '0'
+	return string(next), true
+}
+
+// hasLibertyBesides reports whether the chain at start has a liberty other
+// than except. It stops at the first one it finds.
+func hasLibertyBesides(b *engine.Board, start, except engine.Position) bool {
+	color, _ := b.Get(start)
+	seen := make([]bool, b.Rows*b.Cols)
+	seen[start.Row*b.Cols+start.Col] = true
+	stack := []engine.Position{start}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range neighbors(b, cur) {
+			i := n.Row*b.Cols + n.Col
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			switch v, _ := b.Get(n); v {
+			case 0:
+				if n != except {
+					return true
+				}
+			case color:
+				stack = append(stack, n)
+			}
+		}
+	}
+	return false
+}
+
+// Playout runs a light playout on g with a PlayoutAgent for both colors.
+// See PlayoutWith.
+func Playout(g *Game, rng *rand.Rand, maxMoves int) int {
+	return PlayoutWith(g, &PlayoutAgent{Rand: rng}, maxMoves)
+}
+
+// PlayoutWith lets agent play both colors on g until both players pass,
+// maxMoves moves have been played, or Benson's algorithm shows every point of
+// the board is settled, in which case further play cannot change the score.
+// It returns the number of moves played.
+func PlayoutWith(g *Game, agent Agent, maxMoves int) int {
+	return playout(g, agent, maxMoves, playoutSettleFraction)
+}
+
+// playout is PlayoutWith checking for a settled board once fewer than
+// 1/fraction of the points are empty. A fraction of 0 never checks.
+func playout(g *Game, agent Agent, maxMoves, fraction int) int {
+	area := g.Rows * g.Cols
+	empty := 0
+	g.Board.ForEach(func(_ engine.Position, v int) {
+		if v == 0 {
+			empty++
+		}
+	})
+	played := 0
+	for played < maxMoves && g.ToPlay != None && g.ConsecutivePasses < 2 {
+		if fraction > 0 && empty*fraction < area && played%playoutSettleInterval == 0 && PassAlive(g).Settled() == area {
+			break
+		}
+		m, err := agent.GenMove(g)
+		if err != nil || m.Pass {
+			g.Pass()
+		} else if res, err := g.PlayMove(m.Pos); err != nil {
+			g.Pass()
+		} else {
+			empty += res.Captured - 1
+		}
+		played++
+	}
+	return played
+}
@@ -0,0 +1,113 @@
+package gogame
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// samples is the number of samples per pixel along each axis when
+// antialiasing shape edges.
+const samples = 4
+
+// RenderPNG writes the position to w as a PNG image as o describes.
+func RenderPNG(w io.Writer, g *Game, o RenderOptions) error {
+	return png.Encode(w, RenderImage(g, o))
+}
+
+// RenderImage draws the position as o describes. It draws the same diagram
+// as RenderSVG, with text in a built-in pixel font.
+func RenderImage(g *Game, o RenderOptions) *image.RGBA {
+	d := layout(g, o)
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(d.background), image.Point{}, draw.Src)
+	for _, s := range d.shapes {
+		drawShape(img, s)
+	}
+	return img
+}
+
+func drawShape(img *image.RGBA, s shape) {
+	switch s.kind {
+	case shapeLine:
+		// Diagrams only draw horizontal and vertical lines, which with
+		// square caps are rectangles.
+		x0, x1 := math.Min(s.x, s.x2)-s.size/2, math.Max(s.x, s.x2)+s.size/2
+		y0, y1 := math.Min(s.y, s.y2)-s.size/2, math.Max(s.y, s.y2)+s.size/2
+		fill(img, s.color, x0, y0, x1, y1, func(x, y float64) bool {
+			return x >= x0 && x <= x1 && y >= y0 && y <= y1
+		})
+	case shapeDisc:
+		fill(img, s.color, s.x-s.size, s.y-s.size, s.x+s.size, s.y+s.size, func(x, y float64) bool {
+			return math.Hypot(x-s.x, y-s.y) <= s.size
+		})
+	case shapeRing:
+		outer := s.size + s.width/2
+		fill(img, s.color, s.x-outer, s.y-outer, s.x+outer, s.y+outer, func(x, y float64) bool {
+			return math.Abs(math.Hypot(x-s.x, y-s.y)-s.size) <= s.width/2
+		})
+	case shapeTriangle:
+		// A stroke of width w moves the corners of an equilateral triangle
+		// w further from its center, or w closer on the inside.
+		outer := trianglePoints(s.x, s.y, s.size+s.width)
+		inner := trianglePoints(s.x, s.y, s.size-s.width)
+		r := s.size + s.width
+		fill(img, s.color, s.x-r, s.y-r, s.x+r, s.y+r, func(x, y float64) bool {
+			return inTriangle(outer, x, y) && !inTriangle(inner, x, y)
+		})
+	case shapeSquare:
+		outer, inner := s.size+s.width/2, s.size-s.width/2
+		fill(img, s.color, s.x-outer, s.y-outer, s.x+outer, s.y+outer, func(x, y float64) bool {
+			dx, dy := math.Abs(x-s.x), math.Abs(y-s.y)
+			return dx <= outer && dy <= outer && (dx > inner || dy > inner)
+		})
+	case shapeText:
+		// Capitals are about 0.7 of the font size tall, as in the SVG.
+		text := []rune(s.text)
+		unit := s.size / 10
+		width := float64(len(text)*glyphAdvance-1) * unit
+		left, top := s.x-width/2, s.y-glyphHeight*unit/2
+		fill(img, s.color, left, top, left+width, top+glyphHeight*unit, func(x, y float64) bool {
+			return textInk(text, int(math.Floor((x-left)/unit)), int(math.Floor((y-top)/unit)))
+		})
+	}
+}
+
+// fill draws c over img wherever inside holds within the box x0, y0 to
+// x1, y1, antialiasing edges by sampling each pixel on a grid.
+func fill(img *image.RGBA, c color.RGBA, x0, y0, x1, y1 float64, inside func(x, y float64) bool) {
+	r := image.Rect(int(math.Floor(x0)), int(math.Floor(y0)), int(math.Ceil(x1))+1, int(math.Ceil(y1))+1).Intersect(img.Bounds())
+	if r.Empty() {
+		return
+	}
+	mask := image.NewAlpha(r)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			n := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					if inside(float64(px)+(float64(sx)+0.5)/samples, float64(py)+(float64(sy)+0.5)/samples) {
+						n++
+					}
+				}
+			}
+			mask.SetAlpha(px, py, color.Alpha{A: uint8(n * 0xff / (samples * samples))})
+		}
+	}
+	draw.DrawMask(img, r, image.NewUniform(c), image.Point{}, mask, r.Min, draw.Over)
+}
+
+// inTriangle reports whether x, y lies inside the triangle t.
+func inTriangle(t [3][2]float64, x, y float64) bool {
+	var pos, neg bool
+	for i := range t {
+		a, b := t[i], t[(i+1)%3]
+		cross := (b[0]-a[0])*(y-a[1]) - (b[1]-a[1])*(x-a[0])
+		pos = pos || cross > 0
+		neg = neg || cross < 0
+	}
+	return !(pos && neg)
+}
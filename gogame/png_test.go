@@ -0,0 +1,63 @@
+package gogame
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"boardgame/engine"
+)
+
+func TestRenderPNG(t *testing.T) {
+	g, _ := NewGame(9)
+	playMoves(t, g,
+		Move{Color: Black, Pos: engine.Position{Row: 2, Col: 2}},
+		Move{Color: White, Pos: engine.Position{Row: 6, Col: 6}},
+	)
+	var buf bytes.Buffer
+	if err := RenderPNG(&buf, g, RenderOptions{}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 300 {
+		t.Fatalf("expected 300x300, got %v", b)
+	}
+	at := func(x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+	// Points are 30px apart from (30, 30), drawn on pixel centers.
+	cases := []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{"board", 45, 45, ClassicTheme.Board},
+		{"grid line", 30, 45, ClassicTheme.Line},
+		{"black stone", 30 + 2*30 + 8, 30 + 2*30, ClassicTheme.Black},
+		{"white stone", 30 + 6*30 + 10, 30 + 6*30, ClassicTheme.White},
+		{"last-move ring", 30 + 6*30 + 7, 30 + 6*30, ClassicTheme.Black},
+	}
+	for _, c := range cases {
+		if got := at(c.x, c.y); got != c.want {
+			t.Errorf("%s at (%d, %d): got %v, want %v", c.name, c.x, c.y, got, c.want)
+		}
+	}
+}
+
+func TestTextInk(t *testing.T) {
+	text := []rune("1a")
+	// The 1's stem is its middle column; lowercase is drawn as a capital.
+	if !textInk(text, 2, 3) || textInk(text, 0, 3) {
+		t.Error("unexpected ink in 1")
+	}
+	if !textInk(text, glyphAdvance, 6) || textInk(text, glyphWidth, 3) {
+		t.Error("unexpected ink in A or the space between glyphs")
+	}
+	if textInk(text, 2*glyphAdvance, 3) || textInk([]rune("%"), 2, 3) {
+		t.Error("expected no ink past the text or for unknown characters")
+	}
+}
@@ -0,0 +1,157 @@
+package gogame
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// Move is a single turn: a stone placed at Pos, or a pass.
+type Move struct {
+	Color Color
+	Pos   engine.Position
+	Pass  bool
+}
+
+// MoveRecord is a played move together with its effects, enough to replay or undo it.
+type MoveRecord struct {
+	Move
+	Captured   []engine.Position // opponent stones removed by the move
+	HashBefore string            // position hash (including side to move) before the move
+	HashAfter  string            // position hash after the move
+}
+
+// Setup places stones of one color without playing moves, as in SGF AB/AW
+// properties. It is only allowed before the first move; stones are not
+// checked for liberties, so problem positions can be entered freely.
+func (g *Game) Setup(c Color, positions ...engine.Position) error {
+	if len(g.moves) > 0 {
+		return fmt.Errorf("setup stones must be placed before the first move")
+	}
+	if c != Black && c != White && c != None {
+		return fmt.Errorf("invalid color %s", c)
+	}
+	for _, pos := range positions {
+		if err := g.Board.SetAt(pos, int(c)); err != nil {
+			return err
+		}
+		g.setup = append(g.setup, Move{Color: c, Pos: pos})
+	}
+	g.history = map[string]int{}
+	g.lastHash = serialize(g.Board, g.ToPlay)
+	g.history[g.lastHash]++
+	return nil
+}
+
+// SetToPlay changes the side to move before the first move, e.g. for problems
+// where White starts. Like Setup, it resets the superko history.
+func (g *Game) SetToPlay(c Color) error {
+	if len(g.moves) > 0 {
+		return fmt.Errorf("side to move can only be set before the first move")
+	}
+	if c != Black && c != White {
+		return fmt.Errorf("invalid color %s", c)
+	}
+	g.ToPlay = c
+	g.history = map[string]int{}
+	g.lastHash = serialize(g.Board, g.ToPlay)
+	g.history[g.lastHash]++
+	if g.Clock != nil {
+		g.Clock.Start(c)
+	}
+	return nil
+}
+
+// SetupStones returns the stones placed by Setup, in the order they were placed.
+func (g *Game) SetupStones() []Move {
+	out := make([]Move, len(g.setup))
+	copy(out, g.setup)
+	return out
+}
+
+// Moves returns the moves played so far, oldest first.
+func (g *Game) Moves() []MoveRecord {
+	out := make([]MoveRecord, len(g.moves))
+	copy(out, g.moves)
+	return out
+}
+
+// LastMove returns the most recent move, if any.
+func (g *Game) LastMove() (MoveRecord, bool) {
+	if len(g.moves) == 0 {
+		return MoveRecord{}, false
+	}
+	return g.moves[len(g.moves)-1], true
+}
+
+// Play applies a Move for the player to move, placing a stone or passing.
+func (g *Game) Play(m Move) (MoveResult, error) {
+	if m.Color != None && m.Color != g.ToPlay {
+		return MoveResult{}, fmt.Errorf("it is %s's turn, not %s's", g.ToPlay, m.Color)
+	}
+	if m.Pass {
+		if g.ToPlay == None {
+			return MoveResult{}, fmt.Errorf("game is finished")
+		}
+		g.Pass()
+		return MoveResult{}, nil
+	}
+	return g.PlayMove(m.Pos)
+}
+
+// Replay starts a new game of the given dimensions and plays the moves in order.
+func Replay(rows, cols int, moves []Move) (*Game, error) {
+	g, err := NewRectGame(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range moves {
+		if _, err := g.Play(m); err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+// Undo takes back the last move, restoring captured stones. Games decided by
+// resignation or time cannot be undone; a capture-goal win is reopened.
+func (g *Game) Undo() error {
+	if len(g.moves) == 0 {
+		return fmt.Errorf("no moves to undo")
+	}
+	if strings.HasSuffix(g.Result, "+R") || strings.HasSuffix(g.Result, "+T") {
+		return fmt.Errorf("game is finished")
+	}
+	rec := g.moves[len(g.moves)-1]
+	if !rec.Pass {
+		if err := g.Board.SetAt(rec.Pos, 0); err != nil {
+			return err
+		}
+		for _, p := range rec.Captured {
+			if err := g.Board.SetAt(p, int(other(rec.Color))); err != nil {
+				return err
+			}
+		}
+		g.Captures[rec.Color] -= len(rec.Captured)
+	}
+
+	if g.history[rec.HashAfter]--; g.history[rec.HashAfter] <= 0 {
+		delete(g.history, rec.HashAfter)
+	}
+	g.moves = g.moves[:len(g.moves)-1]
+	g.lastHash = rec.HashBefore
+	g.moveNumber--
+	g.ToPlay = rec.Color
+	g.Winner = None
+	g.Result = ""
+	g.ConsecutivePasses = 0
+	for i := len(g.moves) - 1; i >= 0 && g.moves[i].Pass; i-- {
+		g.ConsecutivePasses++
+	}
+	if g.Clock != nil {
+		g.Clock.Stop()
+		g.Clock.Start(g.ToPlay)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package gogame
+
+import (
+	"fmt"
+
+	"boardgame/engine"
+)
+
+// Score is an area-scoring count of a position.
+type Score struct {
+	Black float64 // stones plus territory
+	White float64 // stones plus territory plus komi
+	Komi  float64
+	// Owners maps every scored point to the color it counts for.
+	Owners map[engine.Position]Color
+	// Dead lists stones removed because they sit in the opponent's pass-alive territory.
+	Dead []engine.Position
+}
+
+// Winner returns the color ahead on points, or None for a tie.
+func (s Score) Winner() Color {
+	switch {
+	case s.Black > s.White:
+		return Black
+	case s.White > s.Black:
+		return White
+	default:
+		return None
+	}
+}
+
+// Result formats the score as an SGF result, e.g. "B+3.5", or "0" for a draw.
+func (s Score) Result() string {
+	margin := s.Black - s.White
+	switch {
+	case margin > 0:
+		return fmt.Sprintf("B+%s", sgfSeconds(margin))
+	case margin < 0:
+		return fmt.Sprintf("W+%s", sgfSeconds(-margin))
+	default:
+		return "0"
+	}
+}
+
+// ScoreArea counts stones plus surrounded empty regions for each color (area
+// scoring). Benson's algorithm marks pass-alive territory first, so opponent
+// stones inside it are removed as dead and the region counts in full.
+// Remaining empty regions count for a color only when it alone borders them.
+func ScoreArea(g *Game, komi float64) Score {
+	s := Score{Komi: komi, White: komi, Owners: map[engine.Position]Color{}}
+	safe := PassAlive(g)
+
+	board := g.Board.Clone()
+	g.Board.ForEach(func(pos engine.Position, v int) {
+		owner := safe.Owner(pos)
+		if v != 0 && owner != None && owner != Color(v) {
+			s.Dead = append(s.Dead, pos)
+			_ = board.SetAt(pos, 0)
+		}
+	})
+
+	seen := map[engine.Position]bool{}
+	board.ForEach(func(pos engine.Position, v int) {
+		if seen[pos] {
+			return
+		}
+		if v != 0 {
+			seen[pos] = true
+			s.Owners[pos] = Color(v)
+			return
+		}
+		region, borders := emptyRegion(board, pos, seen)
+		owner := None
+		if len(borders) == 1 {
+			for c := range borders {
+				owner = c
+			}
+		}
+		if settled := safe.Owner(pos); settled != None {
+			owner = settled
+		}
+		if owner == None {
+			return
+		}
+		for _, p := range region {
+			s.Owners[p] = owner
+		}
+	})
+
+	for _, owner := range s.Owners {
+		if owner == Black {
+			s.Black++
+		} else if owner == White {
+			s.White++
+		}
+	}
+	return s
+}
+
+// emptyRegion flood-fills the empty region containing start, marking points in
+// seen, and returns it together with the set of colors bordering it.
+func emptyRegion(b *engine.Board, start engine.Position, seen map[engine.Position]bool) ([]engine.Position, map[Color]bool) {
+	var region []engine.Position
+	borders := map[Color]bool{}
+	stack := []engine.Position{start}
+	seen[start] = true
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		region = append(region, cur)
+		for _, n := range neighbors(b, cur) {
+			v, _ := b.Get(n)
+			if v != 0 {
+				borders[Color(v)] = true
+				continue
+			}
+			if !seen[n] {
+				seen[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return region, borders
+}
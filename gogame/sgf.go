@@ -0,0 +1,106 @@
+package gogame
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// sgfLetters maps board indexes to SGF coordinate letters; SGF supports up to 52 lines.
+const sgfLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// SGFCoord returns the two-letter SGF coordinate for a position (column then row).
+func SGFCoord(pos engine.Position) string {
+	return string([]byte{sgfLetters[pos.Col], sgfLetters[pos.Row]})
+}
+
+// WriteSGF exports the game as an SGF record: the root node carries the board
+// size, result, and time settings, followed by one node per move. Remaining
+// clock times are attached to the final node.
+func WriteSGF(g *Game) string {
+	var sb strings.Builder
+	sb.WriteString("(;GM[1]FF[4]CA[UTF-8]")
+	if g.Rows == g.Cols {
+		sb.WriteString(fmt.Sprintf("SZ[%d]", g.Cols))
+	} else {
+		sb.WriteString(fmt.Sprintf("SZ[%d:%d]", g.Cols, g.Rows))
+	}
+	if g.Result != "" {
+		sb.WriteString(fmt.Sprintf("RE[%s]", g.Result))
+	}
+	if g.Clock != nil {
+		tc := g.Clock.Control
+		sb.WriteString(fmt.Sprintf("TM[%s]", sgfSeconds(tc.MainTime.Seconds())))
+		if ot := tc.Overtime(); ot != "" {
+			sb.WriteString(fmt.Sprintf("OT[%s]", ot))
+		}
+	}
+
+	for _, color := range []Color{Black, White, None} {
+		var stones []string
+		for _, m := range g.setup {
+			if m.Color == color {
+				stones = append(stones, "["+SGFCoord(m.Pos)+"]")
+			}
+		}
+		if len(stones) > 0 {
+			sb.WriteString(setupProperty(color))
+			sb.WriteString(strings.Join(stones, ""))
+		}
+	}
+	if g.ToPlay == White && len(g.moves) == 0 {
+		sb.WriteString("PL[W]")
+	}
+
+	for _, rec := range g.moves {
+		sb.WriteString("\n;")
+		sb.WriteString(colorLetter(rec.Color))
+		if rec.Pass {
+			sb.WriteString("[]")
+		} else {
+			sb.WriteString("[" + SGFCoord(rec.Pos) + "]")
+		}
+	}
+	if g.Clock != nil {
+		sb.WriteString(sgfTimeLeft(g.Clock))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// setupProperty returns the SGF property that adds stones of a color, or clears points for None.
+func setupProperty(c Color) string {
+	switch c {
+	case Black:
+		return "AB"
+	case White:
+		return "AW"
+	default:
+		return "AE"
+	}
+}
+
+// sgfTimeLeft writes BL/WL (and OB/OW during overtime) for both players.
+func sgfTimeLeft(c *Clock) string {
+	var sb strings.Builder
+	for _, color := range []Color{Black, White} {
+		pc := c.Remaining(color)
+		letter := colorLetter(color)
+		sb.WriteString(fmt.Sprintf("%sL[%s]", letter, sgfSeconds(pc.Left().Seconds())))
+		if pc.Overtime {
+			left := pc.PeriodsLeft
+			if c.Control.System == CanadianTime {
+				left = pc.StonesLeft
+			}
+			sb.WriteString(fmt.Sprintf("O%s[%d]", letter, left))
+		}
+	}
+	return sb.String()
+}
+
+// sgfSeconds formats seconds as SGF reals, without trailing zeros.
+func sgfSeconds(s float64) string {
+	out := fmt.Sprintf("%.1f", s)
+	return strings.TrimSuffix(out, ".0")
+}
@@ -0,0 +1,303 @@
+package gogame
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// SGFNode is one node of an SGF game tree. Children[0] continues the main
+// line; further children are variations.
+type SGFNode struct {
+	Props    map[string][]string
+	Children []*SGFNode
+}
+
+// Get returns the first value of a property, or "" if it is missing.
+func (n *SGFNode) Get(name string) string {
+	if vals := n.Props[name]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// Move returns the node's B or W move, reporting false if it has none. An
+// empty value, or "tt" on boards up to 19x19, is a pass.
+func (n *SGFNode) Move(rows, cols int) (Move, bool, error) {
+	for _, c := range []Color{Black, White} {
+		v, ok := n.Props[colorLetter(c)]
+		if !ok {
+			continue
+		}
+		m := Move{Color: c, Pass: v[0] == "" || (v[0] == "tt" && rows <= 19 && cols <= 19)}
+		if !m.Pass {
+			pos, err := ParseSGFCoord(v[0], rows, cols)
+			if err != nil {
+				return Move{}, false, err
+			}
+			m.Pos = pos
+		}
+		return m, true, nil
+	}
+	return Move{}, false, nil
+}
+
+// MainLine returns the node and its first descendants, in order.
+func (n *SGFNode) MainLine() []*SGFNode {
+	var out []*SGFNode
+	for node := n; node != nil; {
+		out = append(out, node)
+		if len(node.Children) == 0 {
+			break
+		}
+		node = node.Children[0]
+	}
+	return out
+}
+
+// ParseSGF parses the first game tree of an SGF collection and returns its root node.
+func ParseSGF(text string) (*SGFNode, error) {
+	p := &sgfParser{src: text}
+	p.skipSpace()
+	if !p.eat('(') {
+		return nil, fmt.Errorf("sgf: expected '(' at offset %d", p.pos)
+	}
+	root, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type sgfParser struct {
+	src string
+	pos int
+}
+
+func (p *sgfParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *sgfParser) eat(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// sequence parses the nodes and variations of a game tree whose opening
+// parenthesis has been consumed, including the closing one.
+func (p *sgfParser) sequence() (*SGFNode, error) {
+	var first, last *SGFNode
+	for p.eat(';') {
+		node, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = node
+		} else {
+			last.Children = append(last.Children, node)
+		}
+		last = node
+	}
+	if first == nil {
+		return nil, fmt.Errorf("sgf: expected ';' at offset %d", p.pos)
+	}
+	for p.eat('(') {
+		child, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		last.Children = append(last.Children, child)
+	}
+	if !p.eat(')') {
+		return nil, fmt.Errorf("sgf: expected ')' at offset %d", p.pos)
+	}
+	return first, nil
+}
+
+func (p *sgfParser) node() (*SGFNode, error) {
+	node := &SGFNode{Props: map[string][]string{}}
+	for {
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= 'A' && p.src[p.pos] <= 'Z' {
+			p.pos++
+		}
+		if start == p.pos {
+			return node, nil
+		}
+		name := p.src[start:p.pos]
+		var values []string
+		for p.eat('[') {
+			var sb strings.Builder
+			for {
+				if p.pos >= len(p.src) {
+					return nil, fmt.Errorf("sgf: unterminated value for %s", name)
+				}
+				c := p.src[p.pos]
+				p.pos++
+				if c == ']' {
+					break
+				}
+				if c == '\\' && p.pos < len(p.src) {
+					c = p.src[p.pos]
+					p.pos++
+					if c == '\n' {
+						continue // soft line break
+					}
+				}
+				sb.WriteByte(c)
+			}
+			values = append(values, sb.String())
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("sgf: property %s has no value", name)
+		}
+		node.Props[name] = append(node.Props[name], values...)
+	}
+}
+
+// ParseSGFCoord converts a two-letter SGF coordinate (column then row) to a position.
+func ParseSGFCoord(text string, rows, cols int) (engine.Position, error) {
+	if len(text) != 2 {
+		return engine.Position{}, fmt.Errorf("invalid sgf point %q", text)
+	}
+	col := strings.IndexByte(sgfLetters, text[0])
+	row := strings.IndexByte(sgfLetters, text[1])
+	if col < 0 || row < 0 || col >= cols || row >= rows {
+		return engine.Position{}, fmt.Errorf("sgf point %q is off the board", text)
+	}
+	return engine.Position{Row: row, Col: col}, nil
+}
+
+// sgfPoints expands a point list value, which is a single point or a
+// compressed "aa:cc" rectangle.
+func sgfPoints(value string, rows, cols int) ([]engine.Position, error) {
+	from, to, ok := strings.Cut(value, ":")
+	if !ok {
+		pos, err := ParseSGFCoord(value, rows, cols)
+		return []engine.Position{pos}, err
+	}
+	a, err := ParseSGFCoord(from, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ParseSGFCoord(to, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	var out []engine.Position
+	for r := min(a.Row, b.Row); r <= max(a.Row, b.Row); r++ {
+		for c := min(a.Col, b.Col); c <= max(a.Col, b.Col); c++ {
+			out = append(out, engine.Position{Row: r, Col: c})
+		}
+	}
+	return out, nil
+}
+
+// GameFromSGF builds a game of the record's board size (SZ) and loads it
+// with LoadSGF.
+func GameFromSGF(root *SGFNode) (*Game, error) {
+	rows, cols, err := SGFSize(root)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewRectGame(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.LoadSGF(root); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// SGFSize returns the board dimensions given by the root's SZ property,
+// defaulting to 19x19.
+func SGFSize(root *SGFNode) (rows, cols int, err error) {
+	rows, cols = 19, 19
+	if sz := root.Get("SZ"); sz != "" {
+		c, r, rect := strings.Cut(sz, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(c))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid SZ %q", sz)
+		}
+		cols, rows = n, n
+		if rect {
+			if rows, err = strconv.Atoi(strings.TrimSpace(r)); err != nil {
+				return 0, 0, fmt.Errorf("invalid SZ %q", sz)
+			}
+		}
+	}
+	return rows, cols, nil
+}
+
+// LoadSGF replays a record into g, a new game of the record's size: setup
+// stones (AB, AW, AE) and side to move (PL) come from the root node, the B
+// and W moves of the main line are played in order, and a result by
+// resignation or time (RE) ends the game. Variant and CaptureGoal are not
+// part of SGF, so set them on g first.
+func (g *Game) LoadSGF(root *SGFNode) error {
+	rows, cols := g.Rows, g.Cols
+	for _, prop := range []struct {
+		name  string
+		color Color
+	}{{"AB", Black}, {"AW", White}, {"AE", None}} {
+		for _, v := range root.Props[prop.name] {
+			points, err := sgfPoints(v, rows, cols)
+			if err != nil {
+				return fmt.Errorf("%s: %w", prop.name, err)
+			}
+			if err := g.Setup(prop.color, points...); err != nil {
+				return err
+			}
+		}
+	}
+	if pl := root.Get("PL"); pl != "" {
+		c := Black
+		if strings.EqualFold(pl, "W") {
+			c = White
+		}
+		if err := g.SetToPlay(c); err != nil {
+			return err
+		}
+	}
+	for i, node := range root.MainLine() {
+		m, ok, err := node.Move(rows, cols)
+		if err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+		if _, err := g.Play(m); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+	}
+	if g.Winner == None {
+		winner, reason, _ := strings.Cut(strings.ToUpper(root.Get("RE")), "+")
+		switch reason {
+		case "R", "RESIGN":
+			reason = "R"
+		case "T", "TIME":
+			reason = "T"
+		default:
+			reason = ""
+		}
+		switch {
+		case reason != "" && winner == "B":
+			g.decide(Black, reason)
+		case reason != "" && winner == "W":
+			g.decide(White, reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package gogame
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RenderBoardSVG draws the position as a standalone SVG image with
+// coordinates around the board and a ring on the last stone played.
+func RenderBoardSVG(g *Game) string {
+	return RenderSVG(g, RenderOptions{})
+}
+
+// RenderSVG draws the position as a standalone SVG image as o describes.
+func RenderSVG(g *Game, o RenderOptions) string {
+	d := layout(g, o)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", d.width, d.height, d.width, d.height)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="%s"/>`+"\n", d.width, d.height, svgColor(d.background))
+	for _, s := range d.shapes {
+		switch s.kind {
+		case shapeLine:
+			fmt.Fprintf(&sb, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s" stroke-linecap="square"/>`+"\n",
+				num(s.x), num(s.y), num(s.x2), num(s.y2), svgColor(s.color), num(s.size))
+		case shapeDisc:
+			fmt.Fprintf(&sb, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(s.x), num(s.y), num(s.size), svgColor(s.color))
+		case shapeRing:
+			fmt.Fprintf(&sb, `<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s"/>`+"\n",
+				num(s.x), num(s.y), num(s.size), svgColor(s.color), num(s.width))
+		case shapeTriangle:
+			var points []string
+			for _, p := range trianglePoints(s.x, s.y, s.size) {
+				points = append(points, num(p[0])+","+num(p[1]))
+			}
+			fmt.Fprintf(&sb, `<polygon points="%s" fill="none" stroke="%s" stroke-width="%s"/>`+"\n",
+				strings.Join(points, " "), svgColor(s.color), num(s.width))
+		case shapeSquare:
+			fmt.Fprintf(&sb, `<rect x="%s" y="%s" width="%s" height="%s" fill="none" stroke="%s" stroke-width="%s"/>`+"\n",
+				num(s.x-s.size), num(s.y-s.size), num(2*s.size), num(2*s.size), svgColor(s.color), num(s.width))
+		case shapeText:
+			fmt.Fprintf(&sb, `<text x="%s" y="%s" font-family="sans-serif" font-size="%s" text-anchor="middle" dominant-baseline="central" fill="%s">`,
+				num(s.x), num(s.y), num(s.size), svgColor(s.color))
+			_ = xml.EscapeText(&sb, []byte(s.text))
+			sb.WriteString("</text>\n")
+		}
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// trianglePoints returns the corners of an upward equilateral triangle
+// centered on x, y with circumradius r.
+func trianglePoints(x, y, r float64) [3][2]float64 {
+	half := r * math.Sqrt(3) / 2
+	return [3][2]float64{{x, y - r}, {x + half, y + r/2}, {x - half, y + r/2}}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// num formats a coordinate with at most two decimals, dropping trailing zeros.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
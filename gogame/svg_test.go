@@ -0,0 +1,127 @@
+package gogame
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+)
+
+func TestStarPoints(t *testing.T) {
+	cases := map[int]int{5: 0, 9: 5, 13: 5, 19: 9}
+	for size, want := range cases {
+		if got := len(StarPoints(size, size)); got != want {
+			t.Errorf("%dx%d: got %d star points, want %d", size, size, got, want)
+		}
+	}
+	for _, p := range StarPoints(19, 19) {
+		if p.Row != 3 && p.Row != 9 && p.Row != 15 {
+			t.Errorf("unexpected 19x19 star point %+v", p)
+		}
+	}
+}
+
+// svgDoc is the part of a rendered SVG the tests look at.
+type svgDoc struct {
+	Width   string `xml:"width,attr"`
+	Circles []struct {
+		Fill   string `xml:"fill,attr"`
+		Stroke string `xml:"stroke,attr"`
+	} `xml:"circle"`
+	Polygons []struct{} `xml:"polygon"`
+	Rects    []struct{} `xml:"rect"`
+	Texts    []string   `xml:"text"`
+}
+
+func parseSVG(t *testing.T, svg string) svgDoc {
+	t.Helper()
+	var doc svgDoc
+	if err := xml.Unmarshal([]byte(svg), &doc); err != nil {
+		t.Fatalf("invalid SVG: %v\n%s", err, svg)
+	}
+	return doc
+}
+
+func playMoves(t *testing.T, g *Game, moves ...Move) {
+	t.Helper()
+	for _, m := range moves {
+		if _, err := g.Play(m); err != nil {
+			t.Fatalf("play %+v: %v", m, err)
+		}
+	}
+}
+
+func TestRenderBoardSVG(t *testing.T) {
+	g, _ := NewGame(9)
+	playMoves(t, g,
+		Move{Color: Black, Pos: engine.Position{Row: 2, Col: 2}},
+		Move{Color: White, Pos: engine.Position{Row: 6, Col: 6}},
+	)
+	svg := RenderBoardSVG(g)
+	doc := parseSVG(t, svg)
+	if doc.Width != "300" {
+		t.Errorf("expected a 300px wide board, got %s", doc.Width)
+	}
+	counts := map[string]int{}
+	for _, c := range doc.Circles {
+		counts[c.Fill+"/"+c.Stroke]++
+	}
+	// Five star points, the black stone, the white stone's outline, the
+	// white stone, and the ring on it.
+	if counts["#000000/"] != 7 || counts["#ffffff/"] != 1 || counts["none/#000000"] != 1 {
+		t.Errorf("unexpected circles %v", counts)
+	}
+	if !strings.Contains(svg, ">J</text>") || strings.Contains(svg, ">I</text>") {
+		t.Error("expected column labels to skip I")
+	}
+}
+
+func TestRenderSVGOptions(t *testing.T) {
+	g, _ := NewGame(9)
+	playMoves(t, g,
+		Move{Color: Black, Pos: engine.Position{Row: 4, Col: 4}},
+		Move{Color: White, Pos: engine.Position{Row: 4, Col: 5}},
+		Move{Color: Black, Pos: engine.Position{Row: 3, Col: 5}},
+	)
+	root, err := ParseSGF("(;SZ[9]TR[aa][bb]SQ[cc:dd]LB[ee:x & y][ff:B])")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	markup, err := ParseMarkup(root, 9, 9)
+	if err != nil {
+		t.Fatalf("markup: %v", err)
+	}
+	if len(markup.Triangles) != 2 || len(markup.Squares) != 4 || markup.Labels[engine.Position{Row: 4, Col: 4}] != "x & y" {
+		t.Fatalf("unexpected markup %+v", markup)
+	}
+	svg := RenderSVG(g, RenderOptions{CellSize: 20, Theme: PrintTheme, HideCoordinates: true, NumberFrom: 2, Markup: markup})
+	doc := parseSVG(t, svg)
+	// Eight lines of 20px plus half a cell of margin on each side.
+	if doc.Width != "180" {
+		t.Errorf("expected a 180px wide board, got %s", doc.Width)
+	}
+	if len(doc.Polygons) != 2 || len(doc.Rects) != 1+4 {
+		t.Errorf("expected 2 triangles and 4 squares, got %d polygons and %d rects", len(doc.Polygons), len(doc.Rects))
+	}
+	// Moves 2 and 3 are numbered, so the last move has no ring, and the
+	// first stone carries a label instead.
+	if got := strings.Join(doc.Texts, ","); got != "3,2,x & y,B" {
+		t.Errorf("unexpected text %q", got)
+	}
+	for _, c := range doc.Circles {
+		if c.Fill == "none" {
+			t.Error("expected no last-move ring on a numbered stone")
+		}
+	}
+	if strings.Contains(svg, "#dcb35c") {
+		t.Error("expected the print theme's white board")
+	}
+
+	if _, err := ParseMarkup(&SGFNode{Props: map[string][]string{"LB": {"zz:A"}}}, 9, 9); err == nil {
+		t.Error("expected a label off the board to be rejected")
+	}
+	if _, err := ParseTheme("neon"); err == nil {
+		t.Error("expected an unknown theme to be rejected")
+	}
+}
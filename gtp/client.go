@@ -0,0 +1,212 @@
+// Package gtp speaks the Go Text Protocol (GTP version 2): Engine drives an
+// external engine and Server answers for a gogame.Agent.
+package gtp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// ErrResign is returned by GenMove when the engine resigns.
+var ErrResign = errors.New("engine resigned")
+
+// Engine is a GTP engine, usually a subprocess. It implements gogame.Agent,
+// replaying the game to the engine before each move request.
+type Engine struct {
+	Komi float64
+
+	cmd    *exec.Cmd // nil for engines not started by Start
+	w      io.WriteCloser
+	r      *bufio.Reader
+	size   int           // board size the engine was last set up for; 0 before setup
+	synced []gogame.Move // setup stones and moves the engine has seen, in order
+}
+
+// Start runs command (split on spaces, e.g. "gnugo --mode gtp") as an engine.
+func Start(command string) (*Engine, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errors.New("empty GTP engine command")
+	}
+	cmd := exec.Command(fields[0], fields[1:]...) //nolint:gosec // the user chooses the engine to run
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", fields[0], err)
+	}
+	e := NewEngine(r, w)
+	e.cmd = cmd
+	return e, nil
+}
+
+// NewEngine talks GTP over an existing connection: commands are written to
+// w and responses read from r.
+func NewEngine(r io.Reader, w io.WriteCloser) *Engine {
+	return &Engine{r: bufio.NewReader(r), w: w}
+}
+
+// Command sends one command and returns the response text without its
+// "= " prefix. A "? " failure response is returned as an error.
+func (e *Engine) Command(command string) (string, error) {
+	if _, err := fmt.Fprintf(e.w, "%s\n", command); err != nil {
+		return "", err
+	}
+	var lines []string
+	for {
+		line, err := e.r.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if err != nil {
+			return "", fmt.Errorf("reading response to %q: %w", command, err)
+		}
+		if line == "" {
+			if len(lines) == 0 {
+				continue // stray blank line before the response
+			}
+			break
+		}
+		lines = append(lines, line)
+	}
+	resp := strings.Join(lines, "\n")
+	switch {
+	case strings.HasPrefix(resp, "="):
+		return strings.TrimSpace(resp[1:]), nil
+	case strings.HasPrefix(resp, "?"):
+		return "", fmt.Errorf("%s: %s", command, strings.TrimSpace(resp[1:]))
+	default:
+		return "", fmt.Errorf("%s: malformed response %q", command, resp)
+	}
+}
+
+// GenMove implements gogame.Agent: it brings the engine up to date with g
+// and asks it to play for the side to move.
+func (e *Engine) GenMove(g *gogame.Game) (gogame.Move, error) {
+	if g.ToPlay == gogame.None {
+		return gogame.Move{}, errors.New("game is finished")
+	}
+	if err := e.sync(g); err != nil {
+		return gogame.Move{}, err
+	}
+	resp, err := e.Command("genmove " + colorName(g.ToPlay))
+	if err != nil {
+		return gogame.Move{}, err
+	}
+	if strings.EqualFold(resp, "resign") {
+		return gogame.Move{}, ErrResign
+	}
+	m, err := gogame.GTPNotation{}.ParseMove(g, resp)
+	if err != nil {
+		return gogame.Move{}, fmt.Errorf("engine played %q: %w", resp, err)
+	}
+	e.synced = append(e.synced, m)
+	return m, nil
+}
+
+// sync sends the setup stones and moves of g the engine has not seen. If
+// the game no longer extends what the engine has, after an undo for
+// example, the engine's board is cleared and the game replayed.
+func (e *Engine) sync(g *gogame.Game) error {
+	if g.Rows != g.Cols {
+		return errors.New("GTP engines only play on square boards")
+	}
+	if g.Rows > MaxBoardSize {
+		return fmt.Errorf("GTP engines play on boards of at most %d lines", MaxBoardSize)
+	}
+	want, err := setupPosition(g)
+	if err != nil {
+		return err
+	}
+	for _, rec := range g.Moves() {
+		want = append(want, rec.Move)
+	}
+	if e.size != g.Rows || len(e.synced) > len(want) || !sameMoves(e.synced, want[:len(e.synced)]) {
+		for _, cmd := range []string{fmt.Sprintf("boardsize %d", g.Rows), "clear_board", fmt.Sprintf("komi %g", e.Komi)} {
+			if _, err := e.Command(cmd); err != nil {
+				return err
+			}
+		}
+		e.size, e.synced = g.Rows, nil
+	}
+	for _, m := range want[len(e.synced):] {
+		vertex := gogame.GTPNotation{}.FormatMove(g, m)
+		if _, err := e.Command(fmt.Sprintf("play %s %s", colorName(m.Color), vertex)); err != nil {
+			return err
+		}
+		e.synced = append(e.synced, m)
+	}
+	return nil
+}
+
+// setupPosition returns the stones g's setup leaves on the board, in board
+// order, as the moves that place them. GTP has no setup command, so they
+// are sent as plays; that only reproduces the position if every chain in it
+// has a liberty, as then no play captures or is suicide.
+func setupPosition(g *gogame.Game) ([]gogame.Move, error) {
+	final := map[engine.Position]gogame.Color{}
+	for _, m := range g.SetupStones() {
+		final[m.Pos] = m.Color // later setup, including AE's None, wins
+	}
+	pos, err := gogame.NewRectGame(g.Rows, g.Cols)
+	if err != nil {
+		return nil, err
+	}
+	var stones []gogame.Move
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Cols; col++ {
+			p := engine.Position{Row: row, Col: col}
+			if c := final[p]; c != gogame.None {
+				stones = append(stones, gogame.Move{Color: c, Pos: p})
+				if err := pos.Setup(c, p); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, m := range stones {
+		if _, libs, err := pos.ChainAt(m.Pos); err != nil || len(libs) == 0 {
+			return nil, fmt.Errorf("setup stone at %s has no liberties, so a GTP engine cannot reproduce the position",
+				gogame.GTPNotation{}.FormatMove(g, m))
+		}
+	}
+	return stones, nil
+}
+
+// Close asks the engine to quit and waits for it to exit.
+func (e *Engine) Close() error {
+	_, _ = e.Command("quit")
+	err := e.w.Close()
+	if e.cmd != nil {
+		if werr := e.cmd.Wait(); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+func sameMoves(a, b []gogame.Move) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func colorName(c gogame.Color) string {
+	if c == gogame.White {
+		return "white"
+	}
+	return "black"
+}
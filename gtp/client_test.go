@@ -0,0 +1,136 @@
+package gtp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// fakeEngine answers GTP commands, replying to genmove from a script and
+// recording everything it was sent.
+func fakeEngine(t *testing.T, replies ...string) (*Engine, *[]string) {
+	t.Helper()
+	toEngine, fromClient := io.Pipe()
+	fromEngine, toClient := io.Pipe()
+	var received []string
+	go func() {
+		defer toClient.Close()
+		in := bufio.NewScanner(toEngine)
+		for in.Scan() {
+			cmd := in.Text()
+			received = append(received, cmd)
+			switch {
+			case cmd == "quit":
+				fmt.Fprint(toClient, "=\n\n")
+				return
+			case strings.HasPrefix(cmd, "genmove"):
+				fmt.Fprintf(toClient, "= %s\n\n", replies[0])
+				replies = replies[1:]
+			case strings.HasPrefix(cmd, "boardsize") && cmd != "boardsize 5":
+				fmt.Fprint(toClient, "? unacceptable size\n\n")
+			default:
+				fmt.Fprint(toClient, "=\n\n")
+			}
+		}
+	}()
+	return NewEngine(fromEngine, fromClient), &received
+}
+
+func TestEngineGenMove(t *testing.T) {
+	e, received := fakeEngine(t, "C3", "pass", "resign")
+	e.Komi = 0.5
+	g, _ := gogame.NewGame(5)
+	if _, err := g.PlayMove(engine.Position{Row: 3, Col: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := e.GenMove(g)
+	if err != nil {
+		t.Fatalf("genmove: %v", err)
+	}
+	if m.Color != gogame.White || m.Pos != (engine.Position{Row: 2, Col: 2}) {
+		t.Fatalf("expected White C3, got %+v", m)
+	}
+	want := []string{"boardsize 5", "clear_board", "komi 0.5", "play black B2", "genmove white"}
+	if strings.Join(*received, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected commands %q", *received)
+	}
+
+	// Only new moves are sent once the engine is in sync.
+	if _, err := g.Play(m); err != nil {
+		t.Fatal(err)
+	}
+	g.Pass()
+	if m, err = e.GenMove(g); err != nil || !m.Pass {
+		t.Fatalf("expected a pass, got %+v (%v)", m, err)
+	}
+	if got := (*received)[5:]; strings.Join(got, "|") != "play black pass|genmove white" {
+		t.Fatalf("unexpected commands after syncing %q", got)
+	}
+
+	// Taking moves back replays the game from scratch.
+	_ = g.Undo()
+	_ = g.Undo()
+	*received = nil
+	if _, err := e.GenMove(g); err != ErrResign {
+		t.Fatalf("expected ErrResign, got %v", err)
+	}
+	if (*received)[1] != "clear_board" {
+		t.Fatalf("expected the engine to be reset, got %q", *received)
+	}
+
+	big, _ := gogame.NewGame(9)
+	if _, err := e.GenMove(big); err == nil || !strings.Contains(err.Error(), "unacceptable size") {
+		t.Fatalf("expected the engine's failure response, got %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestEngineSendsNetSetupPosition(t *testing.T) {
+	e, received := fakeEngine(t, "D4")
+	g, _ := gogame.NewGame(5)
+	steps := []struct {
+		color gogame.Color
+		pos   engine.Position
+	}{
+		{gogame.Black, engine.Position{Row: 4, Col: 0}},
+		{gogame.Black, engine.Position{Row: 3, Col: 1}},
+		{gogame.None, engine.Position{Row: 4, Col: 0}}, // cleared again, as by SGF AE
+		{gogame.White, engine.Position{Row: 2, Col: 2}},
+	}
+	for _, s := range steps {
+		if err := g.Setup(s.color, s.pos); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.GenMove(g); err != nil {
+		t.Fatalf("genmove: %v", err)
+	}
+	want := []string{"boardsize 5", "clear_board", "komi 0", "play white C3", "play black B2", "genmove black"}
+	if strings.Join(*received, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected commands %q", *received)
+	}
+
+	// A white stone set up in the corner with no liberties would be
+	// captured or refused if played, so the position is refused instead.
+	dead, _ := gogame.NewGame(5)
+	_ = dead.Setup(gogame.White, engine.Position{Row: 4, Col: 0})
+	_ = dead.Setup(gogame.Black, engine.Position{Row: 3, Col: 0}, engine.Position{Row: 4, Col: 1})
+	if _, err := e.GenMove(dead); err == nil || !strings.Contains(err.Error(), "A1 has no liberties") {
+		t.Fatalf("expected the position to be refused, got %v", err)
+	}
+	big, _ := gogame.NewGame(MaxBoardSize + 1)
+	if _, err := e.GenMove(big); err == nil || !strings.Contains(err.Error(), "at most 25 lines") {
+		t.Fatalf("expected a board GTP cannot describe to be refused, got %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
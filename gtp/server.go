@@ -0,0 +1,277 @@
+package gtp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// DefaultBoardSize is the board a Server plays on until told otherwise.
+const DefaultBoardSize = 19
+
+// MaxBoardSize is the largest board GTP can describe: vertex columns are
+// the letters A to Z without I.
+const MaxBoardSize = 25
+
+// knownCommands lists the commands a Server answers, for list_commands.
+var knownCommands = []string{
+	"protocol_version", "name", "version", "known_command", "list_commands", "quit",
+	"boardsize", "clear_board", "komi", "play", "genmove", "undo", "showboard", "final_score",
+}
+
+// Server answers GTP commands with a gogame.Agent choosing the moves, so
+// that GUIs and other engines can play against it.
+type Server struct {
+	Agent   gogame.Agent
+	Name    string
+	Version string
+	Komi    float64 // used by final_score; MCTS agents are updated by komi too
+
+	game *gogame.Game
+	undo []*gogame.Game // the game before each play or genmove, for undo
+}
+
+// Serve reads commands from r and writes responses to w until quit or the
+// end of input.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	in := bufio.NewScanner(r)
+	for in.Scan() {
+		line := in.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		id := ""
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			id, fields = fields[0], fields[1:]
+			if len(fields) == 0 {
+				continue
+			}
+		}
+		resp, err := s.Handle(fields[0], fields[1:])
+		status := "="
+		if err != nil {
+			status, resp = "?", err.Error()
+		}
+		if resp != "" {
+			resp = " " + resp
+		}
+		if _, werr := fmt.Fprintf(w, "%s%s%s\n\n", status, id, resp); werr != nil {
+			return werr
+		}
+		if fields[0] == "quit" {
+			return nil
+		}
+	}
+	return in.Err()
+}
+
+// Handle runs one command and returns its response text.
+func (s *Server) Handle(name string, args []string) (string, error) {
+	if s.game == nil {
+		if err := s.newGame(DefaultBoardSize); err != nil {
+			return "", err
+		}
+	}
+	switch name {
+	case "protocol_version":
+		return "2", nil
+	case "name":
+		return s.Name, nil
+	case "version":
+		return s.Version, nil
+	case "known_command":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		for _, c := range knownCommands {
+			if c == args[0] {
+				return "true", nil
+			}
+		}
+		return "false", nil
+	case "list_commands":
+		return strings.Join(knownCommands, "\n"), nil
+	case "quit":
+		return "", nil
+	case "boardsize":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		size, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", errors.New("syntax error")
+		}
+		if size > MaxBoardSize {
+			return "", errors.New("unacceptable size")
+		}
+		if err := s.newGame(size); err != nil {
+			return "", errors.New("unacceptable size")
+		}
+		return "", nil
+	case "clear_board":
+		return "", s.newGame(s.game.Rows)
+	case "komi":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		komi, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return "", errors.New("syntax error")
+		}
+		s.Komi = komi
+		if a, ok := s.Agent.(*gogame.MCTSAgent); ok {
+			a.Komi = komi
+		}
+		return "", nil
+	case "play":
+		if len(args) != 2 {
+			return "", errors.New("syntax error")
+		}
+		m, err := s.parseMove(args[0], args[1])
+		if err != nil {
+			return "", err
+		}
+		if err := s.play(m); err != nil {
+			return "", fmt.Errorf("illegal move: %v", err)
+		}
+		return "", nil
+	case "genmove":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		c, err := parseColor(args[0])
+		if err != nil {
+			return "", err
+		}
+		return s.genMove(c)
+	case "undo":
+		if len(s.undo) == 0 {
+			return "", errors.New("cannot undo")
+		}
+		s.game, s.undo = s.undo[len(s.undo)-1], s.undo[:len(s.undo)-1]
+		return "", nil
+	case "showboard":
+		return "\n" + gogame.RenderBoardASCII(s.game), nil
+	case "final_score":
+		return gogame.ScoreArea(s.game, s.Komi).Result(), nil
+	default:
+		return "", errors.New("unknown command")
+	}
+}
+
+func (s *Server) newGame(size int) error {
+	g, err := gogame.NewGame(size)
+	if err != nil {
+		return err
+	}
+	s.game, s.undo = g, nil
+	return nil
+}
+
+// genMove asks the agent for a move for c and plays it.
+func (s *Server) genMove(c gogame.Color) (string, error) {
+	g, err := s.toPlay(c)
+	if err != nil {
+		return "", err
+	}
+	m, err := s.Agent.GenMove(g.Clone())
+	if errors.Is(err, ErrResign) {
+		return "resign", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	m.Color = c
+	if err := s.playOn(g, m); err != nil {
+		return "", fmt.Errorf("agent chose an illegal move: %v", err)
+	}
+	return gogame.GTPNotation{}.FormatMove(g, m), nil
+}
+
+func (s *Server) play(m gogame.Move) error {
+	g, err := s.toPlay(m.Color)
+	if err != nil {
+		return err
+	}
+	return s.playOn(g, m)
+}
+
+// playOn plays m in g, a copy of the game, and makes g the game, keeping
+// the old one for undo.
+func (s *Server) playOn(g *gogame.Game, m gogame.Move) error {
+	if _, err := g.Play(m); err != nil {
+		return err
+	}
+	s.undo = append(s.undo, s.game)
+	s.game = g
+	return nil
+}
+
+// toPlay returns a copy of the game with c to move. GTP lets either color
+// move at any time, for handicap stones for example, but a game only
+// changes its side to move before the first move. After that the position
+// is carried over to a new game as setup stones, which forgets the earlier
+// positions for superko.
+func (s *Server) toPlay(c gogame.Color) (*gogame.Game, error) {
+	g := s.game.Clone()
+	switch {
+	case g.ToPlay == c:
+		return g, nil
+	case g.ToPlay == gogame.None:
+		return nil, errors.New("game is finished")
+	case len(g.Moves()) == 0:
+		return g, g.SetToPlay(c)
+	}
+	next, err := gogame.NewRectGame(g.Rows, g.Cols)
+	if err != nil {
+		return nil, err
+	}
+	next.Variant, next.CaptureGoal = g.Variant, g.CaptureGoal
+	for _, color := range []gogame.Color{gogame.Black, gogame.White} {
+		var stones []engine.Position
+		g.Board.ForEach(func(pos engine.Position, v int) {
+			if gogame.Color(v) == color {
+				stones = append(stones, pos)
+			}
+		})
+		if err := next.Setup(color, stones...); err != nil {
+			return nil, err
+		}
+		next.Captures[color] = g.Captures[color]
+	}
+	return next, next.SetToPlay(c)
+}
+
+func (s *Server) parseMove(color, vertex string) (gogame.Move, error) {
+	c, err := parseColor(color)
+	if err != nil {
+		return gogame.Move{}, err
+	}
+	m, err := gogame.GTPNotation{}.ParseMove(s.game, vertex)
+	if err != nil {
+		return gogame.Move{}, errors.New("invalid coordinate")
+	}
+	m.Color = c
+	return m, nil
+}
+
+func parseColor(text string) (gogame.Color, error) {
+	switch strings.ToLower(text) {
+	case "b", "black":
+		return gogame.Black, nil
+	case "w", "white":
+		return gogame.White, nil
+	default:
+		return gogame.None, errors.New("invalid color")
+	}
+}
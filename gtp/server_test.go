@@ -0,0 +1,174 @@
+package gtp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// scriptedAgent plays the given moves in order.
+type scriptedAgent []gogame.Move
+
+func (a *scriptedAgent) GenMove(g *gogame.Game) (gogame.Move, error) {
+	if len(*a) == 0 {
+		return gogame.Move{}, ErrResign
+	}
+	m := (*a)[0]
+	*a = (*a)[1:]
+	return m, nil
+}
+
+func TestServerAnswersCommands(t *testing.T) {
+	agent := &scriptedAgent{{Pos: engine.Position{Row: 1, Col: 3}}, {Pass: true}}
+	s := &Server{Agent: agent, Name: "test", Version: "1"}
+	in := strings.Join([]string{
+		"1 protocol_version",
+		"name # comments are ignored",
+		"",
+		"known_command genmove",
+		"boardsize 26",
+		"boardsize 5",
+		"komi 0.5",
+		"play B B2",
+		"play B C3",
+		"genmove w",
+		"final_score",
+		"undo",
+		"undo",
+		"final_score",
+		"genmove w",
+		"play x A1",
+		"genmove b",
+		"undo",
+		"undo",
+		"undo",
+		"frobnicate",
+		"quit",
+		"name",
+	}, "\n")
+	var out strings.Builder
+	if err := s.Serve(strings.NewReader(in), &out); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	want := []string{
+		"=1 2",
+		"= test",
+		"= true",
+		// GTP vertices cannot name a 26th column.
+		"? unacceptable size",
+		"=",
+		"=",
+		"=",
+		// Black may play twice in a row, as for handicap stones.
+		"=",
+		"= D4",
+		// The stones leave the rest of the small board neutral.
+		"= B+0.5",
+		"=",
+		"=",
+		// Undo took back White's move and Black's second stone.
+		"= B+24.5",
+		"= pass",
+		"? invalid color",
+		"= resign",
+		"=",
+		"=",
+		"? cannot undo",
+		"? unknown command",
+		"=",
+	}
+	got := strings.Split(strings.TrimSuffix(out.String(), "\n\n"), "\n\n")
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected responses:\n%s", out.String())
+	}
+}
+
+func TestEngineAgainstServer(t *testing.T) {
+	toServer, fromClient := io.Pipe()
+	fromServer, toClient := io.Pipe()
+	agent := &scriptedAgent{{Pos: engine.Position{Row: 0, Col: 0}}}
+	s := &Server{Agent: agent}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(toServer, toClient)
+		toClient.Close()
+	}()
+
+	e := NewEngine(fromServer, fromClient)
+	g, _ := gogame.NewGame(9)
+	if _, err := g.PlayMove(engine.Position{Row: 4, Col: 4}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := e.GenMove(g)
+	if err != nil {
+		t.Fatalf("genmove: %v", err)
+	}
+	if m.Color != gogame.White || m.Pos != (engine.Position{Row: 0, Col: 0}) {
+		t.Fatalf("expected White A1, got %+v", m)
+	}
+	if _, err := e.GenMove(g); err != ErrResign {
+		t.Fatalf("expected the server to resign, got %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
+
+func TestEngineAgainstServerWithSetupStones(t *testing.T) {
+	toServer, fromClient := io.Pipe()
+	fromServer, toClient := io.Pipe()
+	agent := &scriptedAgent{{Pos: engine.Position{Row: 4, Col: 4}}, {Pos: engine.Position{Row: 0, Col: 0}}}
+	s := &Server{Agent: agent}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(toServer, toClient)
+		toClient.Close()
+	}()
+
+	// A two-stone handicap game: Black's stones are setup and White starts.
+	e := NewEngine(fromServer, fromClient)
+	g, _ := gogame.NewGame(9)
+	if err := g.Setup(gogame.Black, engine.Position{Row: 6, Col: 6}, engine.Position{Row: 2, Col: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetToPlay(gogame.White); err != nil {
+		t.Fatal(err)
+	}
+	genmove := func(want engine.Position) {
+		t.Helper()
+		m, err := e.GenMove(g)
+		if err != nil {
+			t.Fatalf("genmove: %v", err)
+		}
+		if m.Color != gogame.White || m.Pos != want {
+			t.Fatalf("expected White at %+v, got %+v", want, m)
+		}
+		if _, err := g.Play(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	genmove(engine.Position{Row: 4, Col: 4})
+	if _, err := g.PlayMove(engine.Position{Row: 6, Col: 2}); err != nil {
+		t.Fatal(err)
+	}
+	genmove(engine.Position{Row: 0, Col: 0})
+	board, err := e.Command("showboard")
+	if err != nil {
+		t.Fatalf("showboard: %v", err)
+	}
+	if got, want := strings.Count(board, "X"), 3; got != want {
+		t.Fatalf("expected the server to have %d black stones, got:\n%s", want, board)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
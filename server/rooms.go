@@ -0,0 +1,344 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"boardgame/gameapi"
+	"boardgame/gogame"
+	"boardgame/storage"
+)
+
+// Seat names used in room messages and the ?seat= query parameter.
+const (
+	seatBlack     = "black"
+	seatWhite     = "white"
+	seatSpectator = "spectator"
+)
+
+// sendBuffer is how many messages may queue for a client before it is
+// considered too slow and disconnected; it will resync on reconnect.
+const sendBuffer = 32
+
+// maxChatHistory bounds the chat replayed to clients that join or reconnect.
+const maxChatHistory = 100
+
+// Rooms nobody is in are removed once their game is over or they have been
+// empty for roomIdleTimeout, and at most maxRooms are kept open at once.
+const (
+	roomIdleTimeout = time.Hour
+	maxRooms        = 1000
+)
+
+// ChatMessage is one line of room chat.
+type ChatMessage struct {
+	From string    `json:"from"`
+	Text string    `json:"text"`
+	Time time.Time `json:"time"`
+}
+
+// RoomMessage is sent from the server to room clients. Type is "welcome"
+// (on join, with the client's seat, reconnect token, full state, and chat
+// history), "state" (after every move), "chat", "presence", or "error".
+type RoomMessage struct {
+	Type       string            `json:"type"`
+	Seat       string            `json:"seat,omitempty"`
+	Token      string            `json:"token,omitempty"`
+	State      *gameapi.State    `json:"state,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Chat       []ChatMessage     `json:"chat,omitempty"`
+	Players    map[string]string `json:"players,omitempty"`
+	Spectators int               `json:"spectators"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// clientMessage is sent by room clients: a gameapi.Action, or a chat line
+// with Type "chat".
+type clientMessage struct {
+	gameapi.Action
+	Text string `json:"text"`
+}
+
+// seat is a player's claim on a color. The token lets the same player
+// reclaim it after a dropped connection.
+type seat struct {
+	name      string
+	token     string
+	connected int
+}
+
+type roomClient struct {
+	conn  *wsConn
+	name  string
+	color gogame.Color // None for spectators
+	send  chan []byte
+}
+
+// room is a game shared by two seated players and any number of spectators.
+// All fields are guarded by mu.
+type room struct {
+	id      string
+	mu      sync.Mutex
+	game    *gogame.Game
+	seats   map[gogame.Color]*seat
+	clients map[*roomClient]struct{}
+	chat    []ChatMessage
+	// emptySince is when the last client left, or when the room was
+	// created if no one has joined yet.
+	emptySince time.Time
+}
+
+func newRoom(id string, g *gogame.Game) *room {
+	return &room{id: id, game: g, seats: map[gogame.Color]*seat{}, clients: map[*roomClient]struct{}{}, emptySince: time.Now()}
+}
+
+// abandoned reports whether no one is in the room and either its game is
+// over or it has been empty for at least idle.
+func (rm *room) abandoned(now time.Time, idle time.Duration) bool {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if len(rm.clients) > 0 {
+		return false
+	}
+	_, over := rm.game.Status()
+	return over || now.Sub(rm.emptySince) >= idle
+}
+
+// RoomResponse describes a room without joining it.
+type RoomResponse struct {
+	ID         string            `json:"id"`
+	State      gameapi.State     `json:"state"`
+	Players    map[string]string `json:"players"`
+	Spectators int               `json:"spectators"`
+}
+
+func (rm *room) summary() RoomResponse {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return RoomResponse{ID: rm.id, State: gameapi.NewState(rm.game), Players: rm.players(), Spectators: rm.spectators()}
+}
+
+// join seats a client and queues its welcome: a valid token reclaims its
+// seat, otherwise the requested color ("" for either) is taken if free, and
+// anyone else watches.
+func (rm *room) join(c *roomClient, want, token string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	c.color = gogame.None
+	for color, s := range rm.seats {
+		if token != "" && s.token == token {
+			c.color = color
+			c.name = s.name
+		}
+	}
+	if c.color == gogame.None && want != seatSpectator {
+		for _, color := range []gogame.Color{gogame.Black, gogame.White} {
+			if rm.seats[color] == nil && (want == "" || want == seatName(color)) {
+				rm.seats[color] = &seat{name: c.name, token: storage.NewID()}
+				c.color = color
+				break
+			}
+		}
+	}
+	welcome := RoomMessage{Type: "welcome", Seat: seatName(c.color), Chat: rm.chat}
+	if s := rm.seats[c.color]; s != nil {
+		s.connected++
+		welcome.Token = s.token
+	}
+	rm.clients[c] = struct{}{}
+	state := gameapi.NewState(rm.game)
+	welcome.State = &state
+	welcome.Players, welcome.Spectators = rm.players(), rm.spectators()
+	rm.sendTo(c, welcome)
+	rm.broadcast(RoomMessage{Type: "presence", Players: welcome.Players, Spectators: welcome.Spectators}, c)
+}
+
+func (rm *room) leave(c *roomClient) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if _, ok := rm.clients[c]; !ok {
+		return
+	}
+	delete(rm.clients, c)
+	close(c.send)
+	if len(rm.clients) == 0 {
+		rm.emptySince = time.Now()
+	}
+	if s := rm.seats[c.color]; s != nil {
+		s.connected--
+	}
+	rm.broadcast(RoomMessage{Type: "presence", Players: rm.players(), Spectators: rm.spectators()}, nil)
+}
+
+// handle applies a client message, broadcasting the result or replying
+// with an error to the sender only.
+func (rm *room) handle(c *roomClient, msg clientMessage) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if strings.EqualFold(msg.Type, "chat") {
+		text := strings.TrimSpace(msg.Text)
+		if text == "" {
+			return
+		}
+		line := ChatMessage{From: c.name, Text: text, Time: time.Now().UTC()}
+		rm.chat = append(rm.chat, line)
+		if len(rm.chat) > maxChatHistory {
+			rm.chat = rm.chat[len(rm.chat)-maxChatHistory:]
+		}
+		rm.broadcast(RoomMessage{Type: "chat", Chat: []ChatMessage{line}}, nil)
+		return
+	}
+
+	text, err := rm.act(c.color, msg.Action)
+	if err != nil {
+		rm.sendTo(c, RoomMessage{Type: "error", Error: err.Error()})
+		return
+	}
+	state := gameapi.NewState(rm.game)
+	rm.broadcast(RoomMessage{Type: "state", State: &state, Message: text}, nil)
+}
+
+// act checks that the seat may take the action and applies it. Players move
+// on their own turn, may resign at any time until the game is over, and may
+// take back their own last move before the opponent replies.
+func (rm *room) act(color gogame.Color, a gameapi.Action) (string, error) {
+	if color == gogame.None {
+		return "", fmt.Errorf("spectators cannot play")
+	}
+	switch strings.ToLower(a.Type) {
+	case "resign":
+		if err := rm.game.Resign(color); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s resigned.", color), nil
+	case "undo":
+		if last, ok := rm.game.LastMove(); !ok || last.Color != color {
+			return "", fmt.Errorf("you can only take back your own last move")
+		}
+	default:
+		if rm.game.ToPlay != color {
+			return "", fmt.Errorf("it is not your turn")
+		}
+	}
+	return gameapi.Apply(rm.game, a)
+}
+
+// broadcast queues msg for every client except skip. Clients whose queue is
+// full are disconnected rather than left with a stale board. Callers hold mu.
+func (rm *room) broadcast(msg RoomMessage, skip *roomClient) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	for c := range rm.clients {
+		if c != skip {
+			rm.queue(c, data)
+		}
+	}
+}
+
+// sendTo queues msg for one client. Callers hold mu.
+func (rm *room) sendTo(c *roomClient, msg RoomMessage) {
+	if data, err := json.Marshal(msg); err == nil {
+		rm.queue(c, data)
+	}
+}
+
+func (rm *room) queue(c *roomClient, data []byte) {
+	select {
+	case c.send <- data:
+	default:
+		c.conn.conn.Close()
+	}
+}
+
+func (rm *room) players() map[string]string {
+	out := map[string]string{}
+	for color, s := range rm.seats {
+		name := s.name
+		if s.connected == 0 {
+			name += " (away)"
+		}
+		out[seatName(color)] = name
+	}
+	return out
+}
+
+func (rm *room) spectators() int {
+	n := 0
+	for c := range rm.clients {
+		if c.color == gogame.None {
+			n++
+		}
+	}
+	return n
+}
+
+func seatName(c gogame.Color) string {
+	switch c {
+	case gogame.Black:
+		return seatBlack
+	case gogame.White:
+		return seatWhite
+	default:
+		return seatSpectator
+	}
+}
+
+// serveRoomSocket upgrades the request and runs the client until it disconnects.
+// Query parameters: name, seat ("black", "white", "spectator", or empty for
+// either free seat), and token to reclaim a seat after reconnecting.
+func serveRoomSocket(w http.ResponseWriter, r *http.Request, rm *room) {
+	q := r.URL.Query()
+	want := strings.ToLower(q.Get("seat"))
+	if want != "" && want != seatBlack && want != seatWhite && want != seatSpectator {
+		writeError(w, http.StatusBadRequest, "seat must be black, white, or spectator")
+		return
+	}
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := strings.TrimSpace(q.Get("name"))
+	if name == "" {
+		name = "guest"
+	}
+	c := &roomClient{conn: conn, name: name, send: make(chan []byte, sendBuffer)}
+	rm.join(c, want, q.Get("token"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for data := range c.send {
+			if err := conn.WriteMessage(data); err != nil {
+				conn.conn.Close()
+				break
+			}
+		}
+		// Drain so that queueing never blocks after a write error.
+		for range c.send {
+		}
+	}()
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			rm.mu.Lock()
+			rm.sendTo(c, RoomMessage{Type: "error", Error: "invalid message"})
+			rm.mu.Unlock()
+			continue
+		}
+		rm.handle(c, msg)
+	}
+	rm.leave(c)
+	<-done
+	conn.Close()
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"boardgame/gogame"
+)
+
+// roomClientConn is an in-process WebSocket client for tests.
+type roomClientConn struct {
+	t  *testing.T
+	ws *wsConn
+}
+
+// testKey is the handshake key used by test clients.
+const testKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+// handshake sends a WebSocket upgrade request for a room, with an Origin
+// header unless origin is empty, and reads the response. The server sees the
+// request's host as "test".
+func handshake(t *testing.T, ts *httptest.Server, roomID, query, origin string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	headers := "Host: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + testKey + "\r\nSec-WebSocket-Version: 13\r\n"
+	if origin != "" {
+		headers += "Origin: " + origin + "\r\n"
+	}
+	fmt.Fprintf(conn, "GET /api/rooms/%s/ws?%s HTTP/1.1\r\n%s\r\n", roomID, query, headers)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	return conn, br, resp
+}
+
+func dialRoom(t *testing.T, ts *httptest.Server, roomID, query string) *roomClientConn {
+	t.Helper()
+	conn, br, resp := handshake(t, ts, roomID, query, "")
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(testKey) {
+		t.Fatalf("unexpected handshake response %s", resp.Status)
+	}
+	c := &roomClientConn{t: t, ws: &wsConn{conn: conn, br: br, client: true}}
+	t.Cleanup(func() { c.ws.Close() })
+	return c
+}
+
+func (c *roomClientConn) send(msg string) {
+	c.t.Helper()
+	if err := c.ws.WriteMessage([]byte(msg)); err != nil {
+		c.t.Fatalf("send: %v", err)
+	}
+}
+
+// expect reads messages until one of the given type arrives.
+func (c *roomClientConn) expect(typ string) RoomMessage {
+	c.t.Helper()
+	_ = c.ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		data, err := c.ws.ReadMessage()
+		if err != nil {
+			c.t.Fatalf("waiting for %s: %v", typ, err)
+		}
+		var msg RoomMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.t.Fatalf("decode %s: %v", data, err)
+		}
+		if msg.Type == typ {
+			return msg
+		}
+	}
+}
+
+func TestRoomMultiplayer(t *testing.T) {
+	ts := httptest.NewServer(New(nil, nil))
+	defer ts.Close()
+	created := decode[RoomResponse](t, call(t, ts, "POST", "/api/rooms", `{"size":9}`, http.StatusCreated))
+
+	alice := dialRoom(t, ts, created.ID, "name=alice")
+	welcome := alice.expect("welcome")
+	if welcome.Seat != seatBlack || welcome.Token == "" {
+		t.Fatalf("expected the first player to sit as Black, got %+v", welcome)
+	}
+	bob := dialRoom(t, ts, created.ID, "name=bob&seat=white")
+	bobWelcome := bob.expect("welcome")
+	if bobWelcome.Seat != seatWhite {
+		t.Fatalf("expected bob to sit as White, got %s", bobWelcome.Seat)
+	}
+	carol := dialRoom(t, ts, created.ID, "name=carol")
+	if w := carol.expect("welcome"); w.Seat != seatSpectator || w.Players["black"] != "alice" || w.Spectators != 1 {
+		t.Fatalf("expected carol to spectate, got %+v", w)
+	}
+
+	// Moves are broadcast to everyone, including spectators.
+	alice.send(`{"type":"play","coord":"E5"}`)
+	for _, c := range []*roomClientConn{alice, bob, carol} {
+		if st := c.expect("state"); st.State.Board[4][4] != 1 || st.Message != "Black played E5." {
+			t.Fatalf("unexpected broadcast %+v", st)
+		}
+	}
+	alice.send(`{"type":"play","coord":"D4"}`)
+	if e := alice.expect("error"); !strings.Contains(e.Error, "not your turn") {
+		t.Fatalf("expected a turn error, got %q", e.Error)
+	}
+	carol.send(`{"type":"pass"}`)
+	if e := carol.expect("error"); !strings.Contains(e.Error, "spectators") {
+		t.Fatalf("expected a spectator error, got %q", e.Error)
+	}
+
+	carol.send(`{"type":"chat","text":"nice move"}`)
+	if chat := bob.expect("chat"); chat.Chat[0].From != "carol" || chat.Chat[0].Text != "nice move" {
+		t.Fatalf("unexpected chat %+v", chat.Chat)
+	}
+
+	// Bob drops and reconnects with his token, getting his seat and the game back.
+	bob.ws.Close()
+	if p := alice.expect("presence"); p.Players["white"] != "bob (away)" {
+		t.Fatalf("expected bob to be away, got %v", p.Players)
+	}
+	bob = dialRoom(t, ts, created.ID, "token="+bobWelcome.Token)
+	resync := bob.expect("welcome")
+	if resync.Seat != seatWhite || resync.State.Board[4][4] != 1 || resync.State.ToPlay != "white" {
+		t.Fatalf("expected bob back as White with the current board, got %+v", resync)
+	}
+	if len(resync.Chat) != 1 || resync.Chat[0].Text != "nice move" {
+		t.Fatalf("expected chat history on reconnect, got %+v", resync.Chat)
+	}
+	bob.send(`{"type":"play","coord":"C3"}`)
+	if st := carol.expect("state"); st.State.Board[6][2] != 2 {
+		t.Fatalf("expected White's move to reach the spectator, got %+v", st)
+	}
+
+	summary := decode[RoomResponse](t, call(t, ts, "GET", "/api/rooms/"+created.ID, "", http.StatusOK))
+	if summary.Players["white"] != "bob" || summary.Spectators != 1 || summary.State.MoveNumber != 2 {
+		t.Fatalf("unexpected room summary %+v", summary)
+	}
+}
+
+func TestRoomRefusesResignAfterTwoPasses(t *testing.T) {
+	ts := httptest.NewServer(New(nil, nil))
+	defer ts.Close()
+	created := decode[RoomResponse](t, call(t, ts, "POST", "/api/rooms", `{"size":9}`, http.StatusCreated))
+	alice := dialRoom(t, ts, created.ID, "name=alice")
+	alice.expect("welcome")
+	bob := dialRoom(t, ts, created.ID, "name=bob")
+	bob.expect("welcome")
+
+	for _, c := range []*roomClientConn{alice, bob} {
+		c.send(`{"type":"pass"}`)
+		alice.expect("state")
+		bob.expect("state")
+	}
+	bob.send(`{"type":"resign"}`)
+	if e := bob.expect("error"); !strings.Contains(e.Error, "finished") {
+		t.Fatalf("expected resigning after two passes to be refused, got %q", e.Error)
+	}
+}
+
+func TestAbandonedRoomsAreRemoved(t *testing.T) {
+	s := New(nil, nil)
+	newTestRoom := func(id string) *room {
+		g, _ := gogame.NewGame(9)
+		rm := newRoom(id, g)
+		s.rooms[id] = rm
+		return rm
+	}
+	newTestRoom("fresh")
+	newTestRoom("finished").game.Resign(gogame.Black)
+	newTestRoom("idle").emptySince = time.Now().Add(-roomIdleTimeout)
+	occupied := newTestRoom("occupied")
+	occupied.emptySince = time.Now().Add(-roomIdleTimeout)
+	occupied.clients[&roomClient{}] = struct{}{}
+
+	s.pruneRooms(time.Now())
+	for id, want := range map[string]bool{"fresh": true, "finished": false, "idle": false, "occupied": true} {
+		if _, ok := s.rooms[id]; ok != want {
+			t.Errorf("room %s kept = %v, want %v", id, ok, want)
+		}
+	}
+
+	for len(s.rooms) < maxRooms {
+		newTestRoom(fmt.Sprint(len(s.rooms)))
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	call(t, ts, "POST", "/api/rooms", `{"size":9}`, http.StatusServiceUnavailable)
+}
+
+func TestRoomSocketChecksOrigin(t *testing.T) {
+	srv := New(nil, nil)
+	srv.AllowedOrigins = []string{"https://friends.example/"}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	created := decode[RoomResponse](t, call(t, ts, "POST", "/api/rooms", `{"size":9}`, http.StatusCreated))
+
+	for origin, want := range map[string]int{
+		"":                        http.StatusSwitchingProtocols,
+		"http://test":             http.StatusSwitchingProtocols,
+		"https://friends.example": http.StatusSwitchingProtocols,
+		"https://evil.example":    http.StatusForbidden,
+		"http://test.evil":        http.StatusForbidden,
+	} {
+		conn, _, resp := handshake(t, ts, created.ID, "seat=spectator", origin)
+		conn.Close()
+		if resp.StatusCode != want {
+			t.Errorf("origin %q: got %s, want %d", origin, resp.Status, want)
+		}
+	}
+}
+
+// frame builds a client frame with the given first header byte. The mask
+// bit and key are added unless unmasked is set.
+func frame(first byte, payload []byte, unmasked bool) []byte {
+	out := []byte{first}
+	if unmasked {
+		out = append(out, byte(len(payload)))
+		return append(out, payload...)
+	}
+	mask := []byte{1, 2, 3, 4}
+	switch {
+	case len(payload) < 126:
+		out = append(out, 0x80|byte(len(payload)))
+	default:
+		out = append(out, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	out = append(out, mask...)
+	for i, b := range payload {
+		out = append(out, b^mask[i%4])
+	}
+	return out
+}
+
+func TestRoomSocketRejectsMalformedFrames(t *testing.T) {
+	ts := httptest.NewServer(New(nil, nil))
+	defer ts.Close()
+	created := decode[RoomResponse](t, call(t, ts, "POST", "/api/rooms", `{"size":9}`, http.StatusCreated))
+
+	chat := []byte(`{"type":"chat","text":"hi"}`)
+	cases := map[string]struct {
+		data []byte
+		code uint16
+	}{
+		"unmasked":               {frame(0x81, chat, true), closeProtocolError},
+		"reserved bits":          {frame(0xC1, chat, false), closeProtocolError},
+		"unknown opcode":         {frame(0x83, nil, false), closeProtocolError},
+		"fragmented ping":        {frame(opPing, nil, false), closeProtocolError},
+		"long ping":              {frame(0x80|opPing, make([]byte, 126), false), closeProtocolError},
+		"stray continuation":     {frame(0x80|opContinuation, chat, false), closeProtocolError},
+		"message inside message": {append(frame(opText, chat[:5], false), frame(0x80|opText, chat, false)...), closeProtocolError},
+		"invalid UTF-8":          {frame(0x81, []byte("{\"type\":\"chat\",\"text\":\"\xff\"}"), false), closeInvalidData},
+	}
+	for name, tc := range cases {
+		c := dialRoom(t, ts, created.ID, "seat=spectator")
+		c.expect("welcome")
+		if _, err := c.ws.conn.Write(tc.data); err != nil {
+			t.Fatalf("%s: write: %v", name, err)
+		}
+		_ = c.ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			_, op, payload, err := c.ws.readFrame()
+			if err != nil {
+				t.Fatalf("%s: expected a close frame, got %v", name, err)
+			}
+			if op != opClose {
+				continue
+			}
+			if len(payload) < 2 || binary.BigEndian.Uint16(payload) != tc.code {
+				t.Errorf("%s: expected close code %d, got %q", name, tc.code, payload)
+			}
+			break
+		}
+	}
+
+	// A well-formed fragmented message with a ping between its frames is
+	// still put together, even when a character is split across frames.
+	c := dialRoom(t, ts, created.ID, "name=dan&seat=spectator")
+	c.expect("welcome")
+	snow := []byte(`{"type":"chat","text":"hi ☃"}`)
+	split := len(snow) - 4 // inside the three bytes of the snowman
+	data := frame(opText, snow[:split], false)
+	data = append(data, frame(0x80|opPing, []byte("x"), false)...)
+	data = append(data, frame(0x80|opContinuation, snow[split:], false)...)
+	if _, err := c.ws.conn.Write(data); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if msg := c.expect("chat"); msg.Chat[0].Text != "hi ☃" {
+		t.Fatalf("expected the fragmented chat, got %+v", msg)
+	}
+}
@@ -0,0 +1,349 @@
+// Package server exposes Go games over HTTP: a JSON API backed by gogame and
+// the static web client that uses it.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"boardgame/gameapi"
+	"boardgame/gogame"
+	"boardgame/storage"
+)
+
+// DefaultKomi is used for scoring when a request does not give one.
+const DefaultKomi = 6.5
+
+// Server holds games in memory and serves the API under /api/ and the web
+// client everywhere else. With a store, games are saved after every change
+// and resumed from it by ID after a restart.
+type Server struct {
+	// AllowedOrigins lists other sites, such as "https://example.com", whose
+	// pages may join rooms; "*" allows any. Pages from this server, and
+	// programs that send no Origin header, may always join.
+	AllowedOrigins []string
+
+	mu    sync.Mutex
+	games map[string]*gogame.Game
+	rooms map[string]*room
+	store storage.Store
+	ui    http.Handler
+}
+
+// New returns a server that serves the files in ui (which may be nil) as the
+// web client and saves games in store (which may also be nil).
+func New(ui fs.FS, store storage.Store) *Server {
+	s := &Server{games: map[string]*gogame.Game{}, rooms: map[string]*room{}, store: store}
+	if ui != nil {
+		s.ui = http.FileServer(http.FS(ui))
+	}
+	return s
+}
+
+// CreateRequest is the body of POST /api/games and POST /api/rooms. Size
+// defaults to 19; Cols defaults to Size.
+type CreateRequest struct {
+	Size     int    `json:"size"`
+	Cols     int    `json:"cols"`
+	Variant  string `json:"variant"`
+	Captures int    `json:"captures"`
+}
+
+// GameResponse is returned by every call that creates, reads, or changes a game.
+type GameResponse struct {
+	ID      string        `json:"id"`
+	State   gameapi.State `json:"state"`
+	Message string        `json:"message,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP routes:
+//
+//	POST /api/games                 create a game
+//	GET  /api/games                 list saved games
+//	GET  /api/games/{id}            get its state, resuming it from the store if needed
+//	POST /api/games/{id}/{action}   play (body {"row","col"} or {"coord"}), pass, undo, or resign
+//	POST /api/games/{id}/save       save it now (games are also saved after every change)
+//	POST /api/games/{id}/load       discard unsaved changes and reload it from the store
+//	GET  /api/games/{id}/score      area score; ?komi= overrides the default
+//	GET  /api/games/{id}/sgf        SGF record
+//	POST /api/rooms                 create a multiplayer room
+//	GET  /api/rooms/{id}            its state, players, and spectator count
+//	GET  /api/rooms/{id}/ws         join it over WebSocket; see serveRoomSocket
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		if s.ui == nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.ui.ServeHTTP(w, r)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/"), "/")
+	switch {
+	case len(parts) > 3:
+		writeError(w, http.StatusNotFound, "not found")
+	case parts[0] == "games":
+		s.serveGames(w, r, parts)
+	case parts[0] == "rooms":
+		s.serveRooms(w, r, parts)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveGames(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.create(w, r)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.list(w)
+	case len(parts) == 1:
+		writeError(w, http.StatusMethodNotAllowed, "use POST to create a game or GET to list saved games")
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.withGame(w, parts[1], func(g *gogame.Game) {
+			writeJSON(w, http.StatusOK, GameResponse{ID: parts[1], State: gameapi.NewState(g)})
+		})
+	case len(parts) == 3 && parts[2] == "score" && r.Method == http.MethodGet:
+		komi := DefaultKomi
+		if k := r.URL.Query().Get("komi"); k != "" {
+			var err error
+			if komi, err = strconv.ParseFloat(k, 64); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid komi")
+				return
+			}
+		}
+		s.withGame(w, parts[1], func(g *gogame.Game) {
+			writeJSON(w, http.StatusOK, gameapi.NewScore(g, komi))
+		})
+	case len(parts) == 3 && parts[2] == "sgf" && r.Method == http.MethodGet:
+		s.withGame(w, parts[1], func(g *gogame.Game) {
+			w.Header().Set("Content-Type", "application/x-go-sgf")
+			fmt.Fprintln(w, gogame.WriteSGF(g))
+		})
+	case len(parts) == 3 && parts[2] == "save" && r.Method == http.MethodPost:
+		s.save(w, parts[1])
+	case len(parts) == 3 && parts[2] == "load" && r.Method == http.MethodPost:
+		s.load(w, parts[1])
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		s.act(w, r, parts[1], parts[2])
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// pruneRooms removes abandoned rooms. s.mu must be held.
+func (s *Server) pruneRooms(now time.Time) {
+	for id, rm := range s.rooms {
+		if rm.abandoned(now, roomIdleTimeout) {
+			delete(s.rooms, id)
+		}
+	}
+}
+
+func (s *Server) serveRooms(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "use POST to create a room")
+			return
+		}
+		g, ok := newGameFromRequest(w, r)
+		if !ok {
+			return
+		}
+		id := storage.NewID()
+		rm := newRoom(id, g)
+		s.mu.Lock()
+		s.pruneRooms(time.Now())
+		full := len(s.rooms) >= maxRooms
+		if !full {
+			s.rooms[id] = rm
+		}
+		s.mu.Unlock()
+		if full {
+			writeError(w, http.StatusServiceUnavailable, "too many rooms are open; try again later")
+			return
+		}
+		writeJSON(w, http.StatusCreated, rm.summary())
+		return
+	}
+	s.mu.Lock()
+	rm, ok := s.rooms[parts[1]]
+	s.mu.Unlock()
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "no room "+parts[1])
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, rm.summary())
+	case len(parts) == 3 && parts[2] == "ws":
+		if err := checkOrigin(r, s.AllowedOrigins); err != nil {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		serveRoomSocket(w, r, rm)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// newGameFromRequest decodes a CreateRequest body and starts the game it
+// describes, writing an error response if it cannot.
+func newGameFromRequest(w http.ResponseWriter, r *http.Request) (*gogame.Game, bool) {
+	var req CreateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return nil, false
+		}
+	}
+	g, err := gameapi.NewGame(gameapi.Options(req))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return g, true
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	g, ok := newGameFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id := storage.NewID()
+	s.mu.Lock()
+	s.games[id] = g
+	s.autosave(id, g)
+	state := gameapi.NewState(g)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, GameResponse{ID: id, State: state})
+}
+
+func (s *Server) act(w http.ResponseWriter, r *http.Request, id, action string) {
+	a := gameapi.Action{Type: action}
+	if action == "play" {
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return
+		}
+		a.Type = action
+	}
+	s.withGame(w, id, func(g *gogame.Game) {
+		msg, err := gameapi.Apply(g, a)
+		switch {
+		case errors.Is(err, gameapi.ErrBadAction):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case err != nil:
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			s.autosave(id, g)
+			writeJSON(w, http.StatusOK, GameResponse{ID: id, State: gameapi.NewState(g), Message: msg})
+		}
+	})
+}
+
+func (s *Server) list(w http.ResponseWriter) {
+	var games []storage.Summary
+	if s.store != nil {
+		var err error
+		if games, err = s.store.List(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if games == nil {
+		games = []storage.Summary{}
+	}
+	writeJSON(w, http.StatusOK, games)
+}
+
+func (s *Server) save(w http.ResponseWriter, id string) {
+	if s.store == nil {
+		writeError(w, http.StatusNotImplemented, "no game store configured")
+		return
+	}
+	s.withGame(w, id, func(g *gogame.Game) {
+		if err := s.store.Save(id, g); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, GameResponse{ID: id, State: gameapi.NewState(g), Message: "Saved game " + id + "."})
+	})
+}
+
+func (s *Server) load(w http.ResponseWriter, id string) {
+	if s.store == nil {
+		writeError(w, http.StatusNotImplemented, "no game store configured")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, err := s.store.Load(id)
+	if err != nil {
+		writeStoreError(w, id, err)
+		return
+	}
+	s.games[id] = g
+	writeJSON(w, http.StatusOK, GameResponse{ID: id, State: gameapi.NewState(g), Message: "Loaded game " + id + "."})
+}
+
+// autosave saves a changed game when the server has a store. Failures are
+// logged rather than failing a move that has already been made. Callers hold mu.
+func (s *Server) autosave(id string, g *gogame.Game) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.Save(id, g); err != nil {
+		log.Printf("saving game %s: %v", id, err)
+	}
+}
+
+// withGame runs f with the game locked, resuming it from the store if it is
+// not in memory, or reports that it does not exist.
+func (s *Server) withGame(w http.ResponseWriter, id string, f func(g *gogame.Game)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	if !ok && s.store != nil {
+		var err error
+		if g, err = s.store.Load(id); err != nil {
+			writeStoreError(w, id, err)
+			return
+		}
+		s.games[id] = g
+		ok = true
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "no game "+id)
+		return
+	}
+	f(g)
+}
+
+func writeStoreError(w http.ResponseWriter, id string, err error) {
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "no game "+id)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"boardgame/gameapi"
+	"boardgame/storage"
+)
+
+func call(t *testing.T, ts *httptest.Server, method, path, body string, want int) []byte {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, path, resp.StatusCode, want, data)
+	}
+	return data
+}
+
+func decode[T any](t *testing.T, data []byte) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return v
+}
+
+func TestGameLifecycle(t *testing.T) {
+	ts := httptest.NewServer(New(nil, nil))
+	defer ts.Close()
+
+	created := decode[GameResponse](t, call(t, ts, "POST", "/api/games", `{"size":5}`, http.StatusCreated))
+	if created.ID == "" || created.State.Rows != 5 || created.State.ToPlay != "black" {
+		t.Fatalf("unexpected new game: %+v", created)
+	}
+	base := "/api/games/" + created.ID
+
+	// Black surrounds the White stone on B4 and captures it.
+	for _, move := range []string{`{"coord":"A4"}`, `{"coord":"B4"}`, `{"coord":"B5"}`, `{"row":4,"col":4}`, `{"coord":"C4"}`, `{"coord":"E4"}`} {
+		call(t, ts, "POST", base+"/play", move, http.StatusOK)
+	}
+	played := decode[GameResponse](t, call(t, ts, "POST", base+"/play", `{"coord":"B3"}`, http.StatusOK))
+	if played.State.Captures["black"] != 1 || played.State.Board[1][1] != 0 || !strings.Contains(played.Message, "captured 1") {
+		t.Fatalf("expected a capture on B4, got %+v", played)
+	}
+	if last := played.State.LastMove; last == nil || *last != (gameapi.Point{Row: 2, Col: 1}) {
+		t.Fatalf("unexpected last move %v", played.State.LastMove)
+	}
+
+	// Occupied points and malformed moves are rejected without changing the game.
+	call(t, ts, "POST", base+"/play", `{"coord":"A4"}`, http.StatusConflict)
+	call(t, ts, "POST", base+"/play", `{"coord":"Z9"}`, http.StatusBadRequest)
+	call(t, ts, "POST", base+"/jump", ``, http.StatusBadRequest)
+
+	undone := decode[GameResponse](t, call(t, ts, "POST", base+"/undo", ``, http.StatusOK))
+	if undone.State.Board[1][1] != 2 || undone.State.MoveNumber != 6 {
+		t.Fatalf("expected undo to restore B4, got %+v", undone.State)
+	}
+
+	call(t, ts, "POST", base+"/pass", ``, http.StatusOK)
+	over := decode[GameResponse](t, call(t, ts, "POST", base+"/pass", ``, http.StatusOK))
+	if !over.State.Over || over.State.ConsecutivePasses != 2 {
+		t.Fatalf("expected two passes to end the game, got %+v", over.State)
+	}
+	score := decode[gameapi.Score](t, call(t, ts, "GET", base+"/score?komi=0.5", ``, http.StatusOK))
+	if score.Komi != 0.5 || score.Result == "" {
+		t.Fatalf("unexpected score %+v", score)
+	}
+	sgf := call(t, ts, "GET", base+"/sgf", ``, http.StatusOK)
+	if !bytes.HasPrefix(sgf, []byte("(;GM[1]")) || !bytes.Contains(sgf, []byte(";B[ab]")) {
+		t.Fatalf("unexpected SGF: %s", sgf)
+	}
+
+	got := decode[GameResponse](t, call(t, ts, "GET", base, ``, http.StatusOK))
+	if got.State.MoveNumber != 8 {
+		t.Fatalf("expected 8 moves, got %d", got.State.MoveNumber)
+	}
+	call(t, ts, "GET", "/api/games/missing", ``, http.StatusNotFound)
+	call(t, ts, "POST", "/api/games", `{"size":1}`, http.StatusBadRequest)
+}
+
+func TestGamesSurviveRestart(t *testing.T) {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := httptest.NewServer(New(nil, store))
+	created := decode[GameResponse](t, call(t, first, "POST", "/api/games", `{"size":9}`, http.StatusCreated))
+	base := "/api/games/" + created.ID
+	call(t, first, "POST", base+"/play", `{"coord":"E5"}`, http.StatusOK)
+	first.Close()
+
+	// A new server resumes the game from the store by ID.
+	second := httptest.NewServer(New(nil, store))
+	defer second.Close()
+	resumed := decode[GameResponse](t, call(t, second, "GET", base, ``, http.StatusOK))
+	if resumed.State.MoveNumber != 1 || resumed.State.Board[4][4] != 1 {
+		t.Fatalf("expected the saved game back, got %+v", resumed.State)
+	}
+	saved := decode[[]storage.Summary](t, call(t, second, "GET", "/api/games", ``, http.StatusOK))
+	if len(saved) != 1 || saved[0].ID != created.ID || saved[0].ToPlay != "white" {
+		t.Fatalf("unexpected saved games %+v", saved)
+	}
+
+	call(t, second, "POST", base+"/play", `{"coord":"C3"}`, http.StatusOK)
+	reloaded := decode[GameResponse](t, call(t, second, "POST", base+"/load", ``, http.StatusOK))
+	if reloaded.State.MoveNumber != 2 {
+		t.Fatalf("expected moves to be saved automatically, got %d", reloaded.State.MoveNumber)
+	}
+	call(t, second, "POST", base+"/save", ``, http.StatusOK)
+	call(t, second, "POST", "/api/games/missing/load", ``, http.StatusNotFound)
+}
+
+func TestServesWebClient(t *testing.T) {
+	ui := fstest.MapFS{"index.html": {Data: []byte("<html>go</html>")}}
+	ts := httptest.NewServer(New(ui, nil))
+	defer ts.Close()
+	if body := call(t, ts, "GET", "/", ``, http.StatusOK); !bytes.Contains(body, []byte("<html>go")) {
+		t.Fatalf("unexpected index: %s", body)
+	}
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // required by the WebSocket handshake, not used for security
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// wsGUID is the fixed key suffix from RFC 6455, section 1.3.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize bounds incoming messages; game messages are tiny.
+const maxMessageSize = 64 << 10
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// wsConn is a minimal RFC 6455 connection carrying text messages. Clients
+// mask the frames they send, as the protocol requires; servers do not.
+type wsConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+	wmu    sync.Mutex
+	closed bool // a close frame has been sent; guarded by wmu
+}
+
+// wsAccept computes the Sec-WebSocket-Accept value for a handshake key.
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID)) //nolint:gosec // see import
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// upgradeWebSocket completes the server side of the opening handshake.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || !headerHas(r.Header, "Connection", "upgrade") ||
+		!headerHas(r.Header, "Upgrade", "websocket") || key == "" {
+		return nil, errors.New("expected a WebSocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, errors.New("unsupported WebSocket version")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be upgraded")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	resp := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAccept(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(resp)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: brw.Reader}, nil
+}
+
+// checkOrigin rejects handshakes from pages on other sites unless allowed
+// lists their origin, so that a page the visitor opens elsewhere cannot use
+// their browser to take a seat (cross-site WebSocket hijacking). Requests
+// without an Origin header do not come from browsers and are allowed.
+func checkOrigin(r *http.Request, allowed []string) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(a), "/"), origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s may not join rooms", origin)
+}
+
+func headerHas(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Close codes from RFC 6455, section 7.4.1.
+const (
+	closeProtocolError = 1002
+	closeInvalidData   = 1007
+	closeTooBig        = 1009
+)
+
+// wsError is a peer's violation of the protocol. It fails the connection
+// with a close frame carrying code.
+type wsError struct {
+	code   uint16
+	reason string
+}
+
+func (e *wsError) Error() string { return "websocket: " + e.reason }
+
+func protocolError(format string, args ...any) error {
+	return &wsError{code: closeProtocolError, reason: fmt.Sprintf(format, args...)}
+}
+
+// ReadMessage returns the next complete data message, answering pings along
+// the way. It returns io.EOF once the peer closes the connection. Frames that
+// break the protocol, and text messages that are not valid UTF-8, fail the
+// connection: ReadMessage sends a close frame with the matching code and
+// returns the error.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	msg, err := c.readMessage()
+	var wsErr *wsError
+	if errors.As(err, &wsErr) {
+		payload := binary.BigEndian.AppendUint16(nil, wsErr.code)
+		_ = c.writeFrame(opClose, append(payload, wsErr.reason...))
+	}
+	return msg, err
+}
+
+func (c *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	started, text := false, false
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			_ = c.writeFrame(opClose, nil)
+			return nil, io.EOF
+		case opText, opBinary:
+			if started {
+				return nil, protocolError("new message before the last one finished")
+			}
+			started, text = true, op == opText
+		case opContinuation:
+			if !started {
+				return nil, protocolError("continuation frame without a message to continue")
+			}
+		default:
+			return nil, protocolError("unknown opcode %d", op)
+		}
+		if len(msg)+len(payload) > maxMessageSize {
+			return nil, &wsError{code: closeTooBig, reason: "message too large"}
+		}
+		msg = append(msg, payload...)
+		if fin {
+			// Text may be split inside a character, so only the whole
+			// message is checked.
+			if text && !utf8.Valid(msg) {
+				return nil, &wsError{code: closeInvalidData, reason: "text message is not valid UTF-8"}
+			}
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads one frame, checking the header rules of RFC 6455, section
+// 5: no extension bits, frames masked by clients and only by clients, and
+// control frames unfragmented with at most 125 bytes of payload.
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(c.br, hdr[:]); err != nil {
+		return
+	}
+	fin, op = hdr[0]&0x80 != 0, hdr[0]&0x0F
+	masked := hdr[1]&0x80 != 0
+	size := uint64(hdr[1] & 0x7F)
+	switch {
+	case hdr[0]&0x70 != 0:
+		err = protocolError("reserved bits set without an extension")
+	case c.client && masked:
+		err = protocolError("masked frame from the server")
+	case !c.client && !masked:
+		err = protocolError("unmasked frame from the client")
+	case op&0x8 != 0 && (!fin || size > 125):
+		err = protocolError("control frames must be unfragmented and at most 125 bytes")
+	}
+	if err != nil {
+		return
+	}
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > maxMessageSize {
+		err = &wsError{code: closeTooBig, reason: "frame too large"}
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// WriteMessage sends a text message. It is safe for concurrent use.
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return net.ErrClosed // nothing may follow a close frame
+	}
+	c.closed = op == opClose
+	frame := []byte{0x80 | op}
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		for i := range frame[start:] {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends a close frame and closes the connection.
+func (c *wsConn) Close() error {
+	_ = c.writeFrame(opClose, nil)
+	return c.conn.Close()
+}
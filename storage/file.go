@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"boardgame/gogame"
+)
+
+// FileStore keeps each game as <id>.json in a directory.
+type FileStore struct {
+	Dir string
+	now func() time.Time
+}
+
+// NewFileStore returns a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir, now: time.Now}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+// Save writes g under id, replacing the file atomically.
+func (s *FileStore) Save(id string, g *gogame.Game) error {
+	if err := CheckID(id); err != nil {
+		return err
+	}
+	rec := newRecord(id, g, s.now().UTC())
+	if old, err := s.read(id); err == nil {
+		rec.Created = old.Created
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(s.path(id), append(data, '\n'))
+}
+
+// WriteFile writes data to path atomically: it writes a temporary file in
+// the same directory and renames it over path, so readers see either the
+// old contents or the new, never a partial file.
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load replays the game stored under id.
+func (s *FileStore) Load(id string) (*gogame.Game, error) {
+	if err := CheckID(id); err != nil {
+		return nil, err
+	}
+	rec, err := s.read(id)
+	if err != nil {
+		return nil, err
+	}
+	return rec.game()
+}
+
+// List returns the stored games, most recently updated first. Unreadable
+// files are skipped.
+func (s *FileStore) List() ([]Summary, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Summary
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		if CheckID(id) != nil {
+			continue
+		}
+		if rec, err := s.read(id); err == nil {
+			out = append(out, rec.Summary)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Updated.After(out[j].Updated) })
+	return out, nil
+}
+
+// Delete removes the game stored under id.
+func (s *FileStore) Delete(id string) error {
+	if err := CheckID(id); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return err
+}
+
+func (s *FileStore) read(id string) (record, error) {
+	var rec record
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return rec, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return rec, err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("reading %s: %w", s.path(id), err)
+	}
+	rec.ID = id
+	return rec, nil
+}
@@ -0,0 +1,122 @@
+// Package storage saves Go games so they can be listed and resumed later.
+// Store is the interface frontends use; FileStore keeps one JSON file per
+// game in a directory, and other backends such as SQLite can implement the
+// same interface.
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"boardgame/gogame"
+)
+
+// ErrNotFound is returned when no game is stored under an ID.
+var ErrNotFound = errors.New("game not found")
+
+// Summary describes a stored game for listings.
+type Summary struct {
+	ID      string    `json:"id"`
+	Rows    int       `json:"rows"`
+	Cols    int       `json:"cols"`
+	Variant string    `json:"variant"`
+	Moves   int       `json:"moves"`
+	ToPlay  string    `json:"toPlay"` // "black", "white", or "" once the game is decided
+	Result  string    `json:"result,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// Store saves and restores games by ID. Saving under an existing ID replaces
+// that game and keeps its creation time.
+type Store interface {
+	Save(id string, g *gogame.Game) error
+	Load(id string) (*gogame.Game, error)
+	// List returns every stored game, most recently updated first.
+	List() ([]Summary, error)
+	Delete(id string) error
+}
+
+// NewID returns a random ID for a game that has not been saved yet.
+func NewID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// CheckID reports whether id is safe to use as a file name: letters,
+// digits, '-' and '_' only.
+func CheckID(id string) error {
+	if id == "" || len(id) > 64 {
+		return fmt.Errorf("invalid game ID %q", id)
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid game ID %q", id)
+		}
+	}
+	return nil
+}
+
+// record is the stored form of a game. The SGF carries the board, setup,
+// moves, and any result; the variant rules are not part of SGF and are kept
+// beside it. Clocks are not saved, so resumed games are untimed.
+type record struct {
+	Summary
+	CaptureGoal int    `json:"captureGoal,omitempty"`
+	SGF         string `json:"sgf"`
+}
+
+func newRecord(id string, g *gogame.Game, now time.Time) record {
+	return record{
+		Summary: Summary{
+			ID:      id,
+			Rows:    g.Rows,
+			Cols:    g.Cols,
+			Variant: g.Variant.String(),
+			Moves:   g.MoveNumber(),
+			ToPlay:  colorName(g.ToPlay),
+			Result:  g.Result,
+			Created: now,
+			Updated: now,
+		},
+		CaptureGoal: g.CaptureGoal,
+		SGF:         gogame.WriteSGF(g),
+	}
+}
+
+// game rebuilds the stored game by replaying its record.
+func (r record) game() (*gogame.Game, error) {
+	root, err := gogame.ParseSGF(r.SGF)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gogame.NewRectGame(r.Rows, r.Cols)
+	if err != nil {
+		return nil, err
+	}
+	if g.Variant, err = gogame.ParseVariant(r.Variant); err != nil {
+		return nil, err
+	}
+	g.CaptureGoal = r.CaptureGoal
+	if err := g.LoadSGF(root); err != nil {
+		return nil, fmt.Errorf("game %s: %w", r.ID, err)
+	}
+	return g, nil
+}
+
+func colorName(c gogame.Color) string {
+	switch c {
+	case gogame.Black:
+		return "black"
+	case gogame.White:
+		return "white"
+	default:
+		return ""
+	}
+}
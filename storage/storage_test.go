@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+
+	g, _ := gogame.NewGame(9)
+	g.Variant = gogame.AtariGo
+	g.CaptureGoal = 2
+	for _, pos := range []engine.Position{{Row: 4, Col: 4}, {Row: 2, Col: 2}} {
+		if _, err := g.PlayMove(pos); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Save("first", g); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	clock = clock.Add(time.Hour)
+	other, _ := gogame.NewRectGame(5, 7)
+	_ = other.Resign(gogame.White)
+	if err := store.Save("second", other); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded, err := store.Load("first")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Hash() != g.Hash() || loaded.Variant != gogame.AtariGo || loaded.CaptureGoal != 2 {
+		t.Fatalf("resumed game differs:\n%s", gogame.RenderBoardASCII(loaded))
+	}
+	if err := loaded.Undo(); err != nil {
+		t.Fatalf("expected the resumed game to keep its history: %v", err)
+	}
+
+	clock = clock.Add(time.Hour)
+	if err := store.Save("first", loaded); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	list, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "first" || list[0].Moves != 1 || list[0].ToPlay != "white" {
+		t.Fatalf("unexpected listing %+v", list)
+	}
+	if !list[0].Created.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected resaving to keep the creation time, got %v", list[0].Created)
+	}
+	if list[1].Result != "B+R" || list[1].Rows != 5 || list[1].Cols != 7 {
+		t.Fatalf("unexpected summary %+v", list[1])
+	}
+
+	if err := store.Delete("second"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Load("second"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.Save("../escape", g); err == nil {
+		t.Fatal("expected an error for an ID that is not a plain name")
+	}
+}
@@ -0,0 +1,41 @@
+package tictactoe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"boardgame/engine"
+)
+
+// Notation writes cells algebraically, a column letter and a row number
+// counted from the bottom ("a1" is the bottom-left corner, "b2" the center
+// of a 3x3 board). On 3x3 boards it also reads a numpad digit, 1-9, laid
+// out like a keypad with 7 at the top left.
+type Notation struct {
+	Rules Rules
+}
+
+// ParseMove implements engine.Notation.
+func (n Notation) ParseMove(g *engine.Game, text string) (engine.Move, error) {
+	s := strings.ToLower(strings.TrimSpace(text))
+	size := n.Rules.Size
+	if len(s) == 1 && size == 3 && s[0] >= '1' && s[0] <= '9' {
+		key := int(s[0] - '1')
+		return engine.MoveTo(n.Rules, g, engine.Position{Row: size - 1 - key/size, Col: key % size}, text)
+	}
+	if len(s) < 2 || s[0] < 'a' || s[0] > 'z' {
+		return engine.Move{}, fmt.Errorf("enter a cell such as b2")
+	}
+	col := int(s[0] - 'a')
+	row, err := strconv.Atoi(s[1:])
+	if err != nil || col >= size || row < 1 || row > size {
+		return engine.Move{}, fmt.Errorf("%q is not a cell on a %dx%d board", text, size, size)
+	}
+	return engine.MoveTo(n.Rules, g, engine.Position{Row: size - row, Col: col}, text)
+}
+
+// FormatMove implements engine.Notation.
+func (n Notation) FormatMove(_ *engine.Game, m engine.Move) string {
+	return fmt.Sprintf("%c%d", 'a'+rune(m.Pos.Col), n.Rules.Size-m.Pos.Row)
+}
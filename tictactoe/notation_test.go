@@ -0,0 +1,66 @@
+package tictactoe
+
+import (
+	"testing"
+
+	"boardgame/engine"
+)
+
+func newTestGame(t *testing.T, r Rules) *engine.Game {
+	t.Helper()
+	g, err := r.NewGame([]engine.Player{{ID: 1, Token: "X"}, {ID: 2, Token: "O"}})
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	return g
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	r := NewRules()
+	n := Notation{Rules: r}
+	g := newTestGame(t, r)
+	cases := []struct {
+		text, numpad string
+		pos          engine.Position
+	}{
+		{"a1", "1", engine.Position{Row: 2, Col: 0}},
+		{"b2", "5", engine.Position{Row: 1, Col: 1}},
+		{"c3", "9", engine.Position{Row: 0, Col: 2}},
+		{"a3", "7", engine.Position{Row: 0, Col: 0}},
+	}
+	for _, c := range cases {
+		want := engine.Move{PlayerID: 1, Pos: c.pos}
+		if got := n.FormatMove(g, want); got != c.text {
+			t.Errorf("format %+v: got %q, want %q", c.pos, got, c.text)
+		}
+		for _, text := range []string{c.text, c.numpad} {
+			if m, err := n.ParseMove(g, text); err != nil || m.PlayerID != want.PlayerID || m.Pos != want.Pos {
+				t.Errorf("parse %q: got %+v (%v)", text, m, err)
+			}
+		}
+	}
+	if m, err := n.ParseMove(g, " B2 "); err != nil || m.Pos != (engine.Position{Row: 1, Col: 1}) {
+		t.Errorf("expected B2 with spaces to parse, got %+v (%v)", m, err)
+	}
+}
+
+func TestNotationRejectsBadInput(t *testing.T) {
+	r := NewRules()
+	n := Notation{Rules: r}
+	g := newTestGame(t, r)
+	if err := r.ApplyMove(g, engine.Move{PlayerID: 1, Pos: engine.Position{Row: 1, Col: 1}}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	g.AdvanceTurn()
+	for _, text := range []string{"", "a", "0", "1a", "d1", "a0", "a4", "ab", "b2", "5"} {
+		if _, err := n.ParseMove(g, text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+
+	// Numpad digits only name cells on 3x3 boards.
+	big := Rules{Size: 4}
+	if _, err := (Notation{Rules: big}).ParseMove(newTestGame(t, big), "5"); err == nil {
+		t.Error("expected a digit to be rejected on 4x4")
+	}
+}
@@ -17,6 +17,24 @@ func NewRules() Rules {
 	return Rules{Size: 3}
 }
 
+func init() {
+	r := NewRules()
+	engine.Register(engine.GameDefinition{
+		Name:       "ttt",
+		Title:      "Tic-tac-toe",
+		MinPlayers: 2,
+		MaxPlayers: 2,
+		Rows:       r.Size,
+		Cols:       r.Size,
+		Players:    []engine.Player{{ID: 1, Name: "X", Token: "X"}, {ID: 2, Name: "O", Token: "O"}},
+		NewGame:    r.NewGame,
+		Rule:       r,
+		Render:     RenderBoard,
+		Notation:   Notation{Rules: r},
+		MoveHelp:   "a cell such as b2 (a1 is bottom left) or a numpad digit 1-9",
+	})
+}
+
 // NewGame constructs a game state for Tic-Tac-Toe.
 func (r Rules) NewGame(players []engine.Player) (*engine.Game, error) {
 	if len(players) != 2 {
@@ -52,7 +70,7 @@ func (r Rules) ApplyMove(g *engine.Game, m engine.Move) error {
 		return err
 	}
 	// If the move completes the game, store the outcome now so Status can surface it.
-	if winnerID := r.findWinner(g.Board); winnerID != 0 {
+	if winnerID := r.Winner(g.Board); winnerID != 0 {
 		g.EndGame(engine.Outcome{Winner: lookupPlayer(g.Players, winnerID)})
 	} else if g.Board.IsFull() {
 		g.EndGame(engine.Outcome{Draw: true})
@@ -65,7 +83,7 @@ func (r Rules) Status(g *engine.Game) (engine.Outcome, bool) {
 	if g.Outcome.Winner != nil || g.Outcome.Draw {
 		return g.Outcome, true
 	}
-	if winnerID := r.findWinner(g.Board); winnerID != 0 {
+	if winnerID := r.Winner(g.Board); winnerID != 0 {
 		return engine.Outcome{Winner: lookupPlayer(g.Players, winnerID)}, true
 	}
 	if g.Board.IsFull() {
@@ -74,8 +92,8 @@ func (r Rules) Status(g *engine.Game) (engine.Outcome, bool) {
 	return engine.Outcome{}, false
 }
 
-// findWinner returns the player ID that has aligned a full row, column, or diagonal.
-func (r Rules) findWinner(b *engine.Board) int {
+// Winner returns the player ID that has aligned a full row, column, or diagonal, or 0 if none has.
+func (r Rules) Winner(b *engine.Board) int {
 	size := r.Size
 	lines := make([][]engine.Position, 0, size*2+2)
 
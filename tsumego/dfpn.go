@@ -0,0 +1,313 @@
+package tsumego
+
+import (
+	"fmt"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// infinity is the proof or disproof number of a solved node.
+const infinity = 1 << 30
+
+// entry is a transposition table record. pn is the proof number (how many
+// leaves must still be proven for the prover to win) and dn the disproof
+// number. length is the number of moves to the end of the proof or disproof
+// once the node is solved.
+type entry struct {
+	pn, dn int
+	length int
+}
+
+func (e entry) solved() bool {
+	return e.pn == 0 || e.dn == 0
+}
+
+// solver runs one df-pn search. The forcer may ignore superko, modelling a
+// side with unlimited ko threats; the other side must obey it.
+type solver struct {
+	g        *gogame.Game
+	prover   gogame.Color
+	forcer   gogame.Color
+	attacker gogame.Color
+	target   []engine.Position
+	region   []engine.Position
+	maxDepth int
+	maxNodes int
+	nodes    int
+	table    map[string]entry
+}
+
+type child struct {
+	move gogame.Move
+	key  string
+}
+
+func newSolver(p Problem, prover, forcer gogame.Color) *solver {
+	return &solver{
+		g:        p.Game.Clone(),
+		prover:   prover,
+		forcer:   forcer,
+		attacker: p.Attacker,
+		target:   p.Target,
+		region:   p.Region,
+		maxDepth: p.MaxDepth,
+		maxNodes: p.MaxNodes,
+		table:    map[string]entry{},
+	}
+}
+
+// run searches from the root until it is solved or the node budget runs out.
+func (s *solver) run() entry {
+	return s.mid(0, infinity, infinity)
+}
+
+// key identifies a node. The depth is part of the key because lines cut off
+// by MaxDepth make the value of a position depend on how deep it was reached.
+func (s *solver) key(depth int) string {
+	return fmt.Sprintf("%s/%d/%d", s.g.Hash(), s.g.ConsecutivePasses, depth)
+}
+
+// lookup returns the stored entry for a key, or the initial estimate for an
+// unexplored node.
+func (s *solver) lookup(key string) entry {
+	if e, ok := s.table[key]; ok {
+		return e
+	}
+	return entry{pn: 1, dn: 1}
+}
+
+// terminal reports whether the current position ends the search and, if so,
+// its value: the attacker wins once any target stone is captured, the
+// defender once the target is pass-alive or both sides pass, and the prover
+// fails when the line gets too long.
+func (s *solver) terminal(depth int) (entry, bool) {
+	winner := gogame.None
+	for _, pos := range s.target {
+		if v, _ := s.g.Board.Get(pos); gogame.Color(v) != opponent(s.attacker) {
+			winner = s.attacker
+			break
+		}
+	}
+	if winner == gogame.None {
+		switch {
+		case s.g.ConsecutivePasses >= 2:
+			winner = opponent(s.attacker)
+		case s.passAlive():
+			winner = opponent(s.attacker)
+		case depth >= s.maxDepth:
+			winner = opponent(s.prover)
+		default:
+			return entry{}, false
+		}
+	}
+	if winner == s.prover {
+		return entry{pn: 0, dn: infinity}, true
+	}
+	return entry{pn: infinity, dn: 0}, true
+}
+
+func (s *solver) passAlive() bool {
+	alive := gogame.PassAlive(s.g)
+	for _, pos := range s.target {
+		if alive.Owner(pos) != opponent(s.attacker) {
+			return false
+		}
+	}
+	return true
+}
+
+// play makes a move on the search board, letting the forcer ignore superko.
+func (s *solver) play(m gogame.Move) error {
+	if m.Pass {
+		s.g.Pass()
+		return nil
+	}
+	if s.g.ToPlay == s.forcer {
+		_, err := s.g.ForcePlay(m.Pos)
+		return err
+	}
+	_, err := s.g.PlayMove(m.Pos)
+	return err
+}
+
+// children generates the legal moves in the region plus a pass, storing the
+// value of any that end the search. The defender never fills its own true
+// eyes.
+func (s *solver) children(depth int) []child {
+	var out []child
+	mover := s.g.ToPlay
+	moves := make([]gogame.Move, 0, len(s.region)+1)
+	for _, pos := range s.region {
+		if v, _ := s.g.Board.Get(pos); v != 0 {
+			continue
+		}
+		if mover != s.attacker && gogame.IsEye(s.g.Board, pos, mover) {
+			continue
+		}
+		moves = append(moves, gogame.Move{Color: mover, Pos: pos})
+	}
+	moves = append(moves, gogame.Move{Color: mover, Pass: true})
+	for _, m := range moves {
+		if err := s.play(m); err != nil {
+			continue
+		}
+		key := s.key(depth + 1)
+		if _, ok := s.table[key]; !ok {
+			if e, ok := s.terminal(depth + 1); ok {
+				s.nodes++
+				s.table[key] = e
+			}
+		}
+		out = append(out, child{move: m, key: key})
+		_ = s.g.Undo()
+	}
+	return out
+}
+
+// mid is the multiple iterative deepening step of df-pn: it expands the
+// current node until its proof number reaches thpn, its disproof number
+// reaches thdn, or the node budget runs out.
+func (s *solver) mid(depth, thpn, thdn int) entry {
+	key := s.key(depth)
+	s.nodes++
+	if e, ok := s.terminal(depth); ok {
+		s.table[key] = e
+		return e
+	}
+	children := s.children(depth)
+	or := s.g.ToPlay == s.prover
+	for {
+		e := s.combine(children, or)
+		if e.pn >= thpn || e.dn >= thdn || e.solved() || s.nodes >= s.maxNodes {
+			s.table[key] = e
+			return e
+		}
+		best, second := s.mostPromising(children, or)
+		c := s.lookup(children[best].key)
+		var cpn, cdn int
+		if or {
+			cpn = min(thpn, second+1)
+			cdn = add(thdn-e.dn, c.dn)
+		} else {
+			cdn = min(thdn, second+1)
+			cpn = add(thpn-e.pn, c.pn)
+		}
+		if err := s.play(children[best].move); err != nil {
+			// The move was legal when generated; treat a failure as a loss for the mover.
+			s.table[children[best].key] = s.loss(or)
+			continue
+		}
+		s.mid(depth+1, cpn, cdn)
+		_ = s.g.Undo()
+	}
+}
+
+// loss is the value of a child that is lost for the player choosing it.
+func (s *solver) loss(or bool) entry {
+	if or {
+		return entry{pn: infinity, dn: 0}
+	}
+	return entry{pn: 0, dn: infinity}
+}
+
+// combine computes a node's proof and disproof numbers from its children: at
+// OR nodes (prover to move) the proof number is the smallest child's and the
+// disproof number the sum; AND nodes are the reverse.
+func (s *solver) combine(children []child, or bool) entry {
+	e := entry{pn: infinity, dn: 0}
+	if !or {
+		e = entry{pn: 0, dn: infinity}
+	}
+	shortest, longest := infinity, 0
+	for _, ch := range children {
+		c := s.lookup(ch.key)
+		if or {
+			e.pn = min(e.pn, c.pn)
+			e.dn = add(e.dn, c.dn)
+		} else {
+			e.pn = add(e.pn, c.pn)
+			e.dn = min(e.dn, c.dn)
+		}
+		if (or && c.pn == 0) || (!or && c.dn == 0) {
+			shortest = min(shortest, c.length)
+		}
+		longest = max(longest, c.length)
+	}
+	// The winner of a solved node picks its quickest win; the loser holds out
+	// as long as possible.
+	switch {
+	case (or && e.pn == 0) || (!or && e.dn == 0):
+		e.length = shortest + 1
+	case e.solved():
+		e.length = longest + 1
+	}
+	return e
+}
+
+// mostPromising returns the index of the most promising child and the second
+// smallest proof (OR) or disproof (AND) number.
+func (s *solver) mostPromising(children []child, or bool) (int, int) {
+	best, first, second := -1, infinity, infinity
+	for i, ch := range children {
+		c := s.lookup(ch.key)
+		n := c.dn
+		if or {
+			n = c.pn
+		}
+		switch {
+		case best < 0 || n < first:
+			best, first, second = i, n, first
+		case n < second:
+			second = n
+		}
+	}
+	return best, second
+}
+
+// pv follows the solved root's best line: the winner's quickest win and the
+// loser's longest resistance at each node.
+func (s *solver) pv() []gogame.Move {
+	var out []gogame.Move
+	depth := 0
+	for {
+		if _, ok := s.terminal(depth); ok {
+			break
+		}
+		e, ok := s.table[s.key(depth)]
+		if !ok || !e.solved() {
+			break
+		}
+		or := s.g.ToPlay == s.prover
+		winning := (or && e.pn == 0) || (!or && e.dn == 0)
+		var next *child
+		nextLen := 0
+		for _, ch := range s.children(depth) {
+			c, ok := s.table[ch.key]
+			if !ok || !c.solved() {
+				continue
+			}
+			wins := (or && c.pn == 0) || (!or && c.dn == 0)
+			switch {
+			case winning && wins && (next == nil || c.length < nextLen),
+				!winning && (next == nil || c.length > nextLen):
+				ch := ch
+				next, nextLen = &ch, c.length
+			}
+		}
+		if next == nil || s.play(next.move) != nil {
+			break
+		}
+		out = append(out, next.move)
+		depth++
+	}
+	for range out {
+		_ = s.g.Undo()
+	}
+	return out
+}
+
+// add sums proof numbers, saturating at infinity.
+func add(a, b int) int {
+	return min(infinity, a+b)
+}
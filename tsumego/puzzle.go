@@ -0,0 +1,159 @@
+package tsumego
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// Verdict is the state of a puzzle attempt.
+type Verdict int
+
+const (
+	// Pending means the attempt is still in progress.
+	Pending Verdict = iota
+	// Correct means the player reached a correct end of the solution tree.
+	Correct
+	// Wrong means the player left the tree or reached a wrong branch.
+	Wrong
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Correct:
+		return "correct"
+	case Wrong:
+		return "wrong"
+	default:
+		return "pending"
+	}
+}
+
+// Puzzle is a problem with a solution tree: the root holds the setup stones and
+// its variations are the player's tries and the answers to them. Branches are
+// judged by the usual conventions: a comment containing RIGHT or CORRECT, or a
+// TE property, marks a correct line; WRONG or INCORRECT, or BM, a wrong one.
+// If no line is marked correct, every line that is not marked wrong is.
+type Puzzle struct {
+	Name    string
+	Problem Problem
+	root    *gogame.SGFNode
+	// marked records whether any branch is explicitly marked correct.
+	marked bool
+}
+
+// LoadPuzzle parses an SGF problem with its solution tree.
+func LoadPuzzle(name, sgf string) (*Puzzle, error) {
+	p, err := LoadProblem(sgf)
+	if err != nil {
+		return nil, err
+	}
+	root, err := gogame.ParseSGF(sgf)
+	if err != nil {
+		return nil, err
+	}
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("puzzle %s has no solution tree", name)
+	}
+	return &Puzzle{Name: name, Problem: p, root: root, marked: branchesMarked(root.Children)}, nil
+}
+
+// Comment returns the problem statement from the root node.
+func (p *Puzzle) Comment() string {
+	return p.root.Get("C")
+}
+
+// Attempt is one try at a puzzle.
+type Attempt struct {
+	Game    *gogame.Game
+	Verdict Verdict
+	// Comment is the comment of the last node reached in the tree.
+	Comment string
+	puzzle  *Puzzle
+	node    *gogame.SGFNode
+}
+
+// Start begins an attempt on a fresh copy of the puzzle position.
+func (p *Puzzle) Start() *Attempt {
+	return &Attempt{Game: p.Problem.Game.Clone(), puzzle: p, node: p.root}
+}
+
+// Play makes the player's move and, if it is in the tree, the tree's answer,
+// which is returned. Moves the rules forbid return an error and leave the
+// attempt unchanged; legal moves outside the tree end the attempt as Wrong.
+func (a *Attempt) Play(pos engine.Position) (*gogame.Move, error) {
+	if a.Verdict != Pending {
+		return nil, fmt.Errorf("attempt is over")
+	}
+	if _, err := a.Game.PlayMove(pos); err != nil {
+		return nil, err
+	}
+	next := a.child(pos)
+	if next == nil {
+		a.Verdict, a.Comment = Wrong, "That move is not part of the solution."
+		return nil, nil
+	}
+	a.enter(next)
+	if a.Verdict != Pending {
+		return nil, nil
+	}
+
+	reply := a.node.Children[0]
+	m, ok, err := reply.Move(a.Game.Rows, a.Game.Cols)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("puzzle %s: answer node has no valid move", a.puzzle.Name)
+	}
+	if _, err := a.Game.Play(m); err != nil {
+		return nil, fmt.Errorf("puzzle %s: answer %w", a.puzzle.Name, err)
+	}
+	a.enter(reply)
+	return &m, nil
+}
+
+// child returns the variation that starts with a move at pos.
+func (a *Attempt) child(pos engine.Position) *gogame.SGFNode {
+	for _, c := range a.node.Children {
+		m, ok, err := c.Move(a.Game.Rows, a.Game.Cols)
+		if err == nil && ok && !m.Pass && m.Pos == pos {
+			return c
+		}
+	}
+	return nil
+}
+
+// enter moves to node and judges the attempt if the line ends there or is
+// marked wrong.
+func (a *Attempt) enter(node *gogame.SGFNode) {
+	a.node = node
+	a.Comment = node.Get("C")
+	switch {
+	case markedWrong(node):
+		a.Verdict = Wrong
+	case len(node.Children) > 0:
+	case markedRight(node) || !a.puzzle.marked:
+		a.Verdict = Correct
+	default:
+		a.Verdict = Wrong
+	}
+}
+
+func markedRight(n *gogame.SGFNode) bool {
+	c := strings.ToUpper(n.Get("C"))
+	return n.Props["TE"] != nil || (strings.Contains(c, "RIGHT") || strings.Contains(c, "CORRECT")) && !strings.Contains(c, "INCORRECT")
+}
+
+func markedWrong(n *gogame.SGFNode) bool {
+	c := strings.ToUpper(n.Get("C"))
+	return n.Props["BM"] != nil || strings.Contains(c, "WRONG") || strings.Contains(c, "INCORRECT")
+}
+
+func branchesMarked(nodes []*gogame.SGFNode) bool {
+	for _, n := range nodes {
+		if markedRight(n) || branchesMarked(n.Children) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+// Package tsumego solves Go life-and-death problems with depth-first
+// proof-number (df-pn) search on top of gogame.
+package tsumego
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// Goal is what the solving side is trying to achieve.
+type Goal int
+
+const (
+	// Kill means the attacker tries to capture the target.
+	Kill Goal = iota
+	// Live means the defender tries to keep the target on the board.
+	Live
+)
+
+func (g Goal) String() string {
+	if g == Live {
+		return "live"
+	}
+	return "kill"
+}
+
+// Status is the life status of the target found by the solver.
+type Status int
+
+const (
+	// Unknown means the search ran out of nodes before reaching an answer.
+	Unknown Status = iota
+	// Alive means the defender lives whatever the attacker does.
+	Alive
+	// Dead means the attacker captures the target whatever the defender does.
+	Dead
+	// Ko means the solving side only reaches its goal by winning a ko.
+	Ko
+)
+
+func (s Status) String() string {
+	switch s {
+	case Alive:
+		return "alive"
+	case Dead:
+		return "dead"
+	case Ko:
+		return "ko"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	defaultMaxNodes = 200000
+	regionMargin    = 1
+)
+
+// Problem describes a life-and-death problem. The player to move in Game
+// moves first; it may be either side.
+type Problem struct {
+	Game     *gogame.Game
+	Attacker gogame.Color
+	Goal     Goal
+	// Target lists defender stones; the attacker wins by capturing any of
+	// them. Empty means the defender's largest chain inside Region.
+	Target []engine.Position
+	// Region lists the points where either side may play. Empty means the
+	// bounding box of all stones, widened by one line.
+	Region []engine.Position
+	// MaxDepth bounds the length of a line; lines that reach it count as a
+	// failure for the solving side. Zero picks a limit from the region size.
+	MaxDepth int
+	// MaxNodes bounds the search effort. Zero means 200000.
+	MaxNodes int
+}
+
+// Result is the answer to a problem.
+type Result struct {
+	Status Status
+	// PV is the principal variation: the solving side's best line if it
+	// succeeds, or the opponent's refutation if it fails.
+	PV    []gogame.Move
+	Nodes int
+}
+
+// Solved reports whether the solving side reaches its goal, possibly by ko.
+func (r Result) Solved(goal Goal) bool {
+	if r.Status == Ko {
+		return true
+	}
+	if goal == Kill {
+		return r.Status == Dead
+	}
+	return r.Status == Alive
+}
+
+// Defender returns the side trying to live.
+func (p Problem) Defender() gogame.Color {
+	return opponent(p.Attacker)
+}
+
+// Solve searches the problem. The first search lets the opponent of the
+// solving side retake kos at will, so a win there needs no ko; if it fails, a
+// second search gives the solving side unlimited ko threats instead, and a
+// win there means the problem is a ko.
+func Solve(p Problem) (Result, error) {
+	if err := p.normalize(); err != nil {
+		return Result{}, err
+	}
+	prover := p.Attacker
+	if p.Goal == Live {
+		prover = p.Defender()
+	}
+	success, failure := Dead, Alive
+	if p.Goal == Live {
+		success, failure = Alive, Dead
+	}
+
+	strict := newSolver(p, prover, opponent(prover))
+	root := strict.run()
+	res := Result{Nodes: strict.nodes}
+	switch {
+	case root.pn == 0:
+		res.Status, res.PV = success, strict.pv()
+		return res, nil
+	case root.dn != 0:
+		return res, nil
+	}
+
+	ko := newSolver(p, prover, prover)
+	root = ko.run()
+	res.Nodes += ko.nodes
+	switch {
+	case root.pn == 0:
+		res.Status, res.PV = Ko, ko.pv()
+	case root.dn == 0:
+		res.Status, res.PV = failure, ko.pv()
+	}
+	return res, nil
+}
+
+// normalize validates the problem and fills in defaults.
+func (p *Problem) normalize() error {
+	if p.Game == nil {
+		return fmt.Errorf("problem has no position")
+	}
+	if p.Attacker != gogame.Black && p.Attacker != gogame.White {
+		return fmt.Errorf("invalid attacker %s", p.Attacker)
+	}
+	if p.Game.ToPlay == gogame.None {
+		return fmt.Errorf("game is finished")
+	}
+	if len(p.Region) == 0 {
+		p.Region = defaultRegion(p.Game.Board)
+	}
+	if len(p.Target) == 0 {
+		p.Target = largestChain(p.Game, p.Region, p.Defender())
+	}
+	if len(p.Target) == 0 {
+		return fmt.Errorf("no %s stones to %s", p.Defender(), p.Goal)
+	}
+	for _, pos := range p.Target {
+		if v, err := p.Game.Board.Get(pos); err != nil || gogame.Color(v) != p.Defender() {
+			return fmt.Errorf("target point %s is not a %s stone", gogame.FormatCoord(pos, p.Game.Rows, p.Game.Cols), p.Defender())
+		}
+	}
+	if p.MaxDepth <= 0 {
+		empty := 0
+		for _, pos := range p.Region {
+			if v, _ := p.Game.Board.Get(pos); v == 0 {
+				empty++
+			}
+		}
+		p.MaxDepth = 2*empty + 4
+	}
+	if p.MaxNodes <= 0 {
+		p.MaxNodes = defaultMaxNodes
+	}
+	return nil
+}
+
+// defaultRegion returns the bounding box of all stones widened by regionMargin.
+func defaultRegion(b *engine.Board) []engine.Position {
+	top, left, bottom, right := b.Rows, b.Cols, -1, -1
+	b.ForEach(func(pos engine.Position, v int) {
+		if v != 0 {
+			top, bottom = min(top, pos.Row), max(bottom, pos.Row)
+			left, right = min(left, pos.Col), max(right, pos.Col)
+		}
+	})
+	if bottom < 0 {
+		return nil
+	}
+	var out []engine.Position
+	for r := max(0, top-regionMargin); r <= min(b.Rows-1, bottom+regionMargin); r++ {
+		for c := max(0, left-regionMargin); c <= min(b.Cols-1, right+regionMargin); c++ {
+			out = append(out, engine.Position{Row: r, Col: c})
+		}
+	}
+	return out
+}
+
+// largestChain returns the stones of color's largest chain with a stone in region.
+func largestChain(g *gogame.Game, region []engine.Position, color gogame.Color) []engine.Position {
+	var best []engine.Position
+	seen := map[engine.Position]bool{}
+	for _, pos := range region {
+		if v, _ := g.Board.Get(pos); gogame.Color(v) != color || seen[pos] {
+			continue
+		}
+		stones, _, err := g.ChainAt(pos)
+		if err != nil {
+			continue
+		}
+		for _, s := range stones {
+			seen[s] = true
+		}
+		if len(stones) > len(best) {
+			best = stones
+		}
+	}
+	return best
+}
+
+// GoalFromComment guesses a problem's goal from text such as "Black to kill"
+// or "White to live", reporting false when neither word appears.
+func GoalFromComment(text string) (Goal, bool) {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "kill"):
+		return Kill, true
+	case strings.Contains(lower, "live"), strings.Contains(lower, "life"):
+		return Live, true
+	}
+	return Kill, false
+}
+
+// LoadProblem reads a problem from SGF: setup stones (AB/AW/AE), board size,
+// and the side to move (PL) come from the root node. The goal comes from the
+// root comment (C) or game name (GN) and defaults to Kill; the side to move
+// is the attacker when killing and the defender when living. Solution moves
+// in the tree are ignored.
+func LoadProblem(sgf string) (Problem, error) {
+	root, err := gogame.ParseSGF(sgf)
+	if err != nil {
+		return Problem{}, err
+	}
+	g, err := gogame.GameFromSGF(&gogame.SGFNode{Props: root.Props})
+	if err != nil {
+		return Problem{}, err
+	}
+	comment := root.Get("C")
+	if _, ok := GoalFromComment(comment); !ok {
+		comment = root.Get("GN")
+	}
+	goal, _ := GoalFromComment(comment)
+	// Without PL, the side to move is whoever plays the first move of the
+	// solution, or the side named in the comment ("White to live").
+	if root.Get("PL") == "" {
+		white := strings.HasPrefix(strings.ToLower(strings.TrimSpace(comment)), "white")
+		if len(root.Children) > 0 {
+			white = len(root.Children[0].Props["W"]) > 0
+		}
+		if white {
+			if err := g.SetToPlay(gogame.White); err != nil {
+				return Problem{}, err
+			}
+		}
+	}
+	p := Problem{Game: g, Goal: goal, Attacker: g.ToPlay}
+	if goal == Live {
+		p.Attacker = opponent(g.ToPlay)
+	}
+	return p, nil
+}
+
+func opponent(c gogame.Color) gogame.Color {
+	switch c {
+	case gogame.Black:
+		return gogame.White
+	case gogame.White:
+		return gogame.Black
+	default:
+		return gogame.None
+	}
+}
@@ -0,0 +1,190 @@
+package tsumego
+
+import (
+	"strings"
+	"testing"
+
+	"boardgame/engine"
+	"boardgame/gogame"
+)
+
+// diagram builds a position from rows of X (Black), O (White), and "." cells.
+func diagram(t *testing.T, toPlay gogame.Color, rows ...string) *gogame.Game {
+	t.Helper()
+	g, err := gogame.NewRectGame(len(rows), len(strings.Fields(rows[0])))
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	for r, line := range rows {
+		for c, cell := range strings.Fields(line) {
+			color := gogame.None
+			switch cell {
+			case "X":
+				color = gogame.Black
+			case "O":
+				color = gogame.White
+			default:
+				continue
+			}
+			if err := g.Setup(color, engine.Position{Row: r, Col: c}); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+		}
+	}
+	if err := g.SetToPlay(toPlay); err != nil {
+		t.Fatalf("set to play: %v", err)
+	}
+	return g
+}
+
+func coords(t *testing.T, g *gogame.Game, texts ...string) []engine.Position {
+	t.Helper()
+	var out []engine.Position
+	for _, text := range texts {
+		pos, err := gogame.ParseCoordRect(text, g.Rows, g.Cols)
+		if err != nil {
+			t.Fatalf("parse %s: %v", text, err)
+		}
+		out = append(out, pos)
+	}
+	return out
+}
+
+func firstMove(t *testing.T, g *gogame.Game, res Result) string {
+	t.Helper()
+	if len(res.PV) == 0 || res.PV[0].Pass {
+		t.Fatalf("expected a principal variation starting with a stone, got %v", res.PV)
+	}
+	return gogame.FormatCoord(res.PV[0].Pos, g.Rows, g.Cols)
+}
+
+// straightThree is a White group on the edge whose eye space is three points
+// in a row: whoever plays the middle point decides its fate.
+var straightThree = []string{
+	". . . O X .",
+	"O O O O X .",
+	"X X X X X .",
+	". . . . . .",
+	". . . . . .",
+}
+
+func TestStraightThree(t *testing.T) {
+	g := diagram(t, gogame.Black, straightThree...)
+	res, err := Solve(Problem{Game: g, Attacker: gogame.Black, Goal: Kill})
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if res.Status != Dead || !res.Solved(Kill) {
+		t.Fatalf("expected Black to kill, got %s", res.Status)
+	}
+	if move := firstMove(t, g, res); move != "B5" {
+		t.Fatalf("expected the vital point B5, got %s", move)
+	}
+
+	g = diagram(t, gogame.White, straightThree...)
+	res, err = Solve(Problem{Game: g, Attacker: gogame.Black, Goal: Live})
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if res.Status != Alive || firstMove(t, g, res) != "B5" {
+		t.Fatalf("expected White to live at B5, got %s %v", res.Status, res.PV)
+	}
+}
+
+func TestKoForLife(t *testing.T) {
+	// White's left group needs the ko at C4/D4: after capturing at D4 and
+	// then at F4, it has eyes at A4, C4, and E4.
+	g := diagram(t, gogame.White,
+		". O X . X . O .",
+		"O O O X O O O O",
+		"X X X X X X X X",
+		". . . . . . . .",
+	)
+	p := Problem{
+		Game:     g,
+		Attacker: gogame.Black,
+		Goal:     Live,
+		Target:   coords(t, g, "B4"),
+		Region:   coords(t, g, "A4", "B4", "C4", "D4", "E4", "F4", "G4", "H4"),
+	}
+	res, err := Solve(p)
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if res.Status != Ko || !res.Solved(Live) {
+		t.Fatalf("expected a ko, got %s %v", res.Status, res.PV)
+	}
+	if move := firstMove(t, g, res); move != "D4" {
+		t.Fatalf("expected the ko capture at D4, got %s", move)
+	}
+	if g.MoveNumber() != 0 {
+		t.Fatalf("solving must not change the problem position")
+	}
+}
+
+func TestLoadProblem(t *testing.T) {
+	sgf := `(;GM[1]FF[4]SZ[6:5]C[White to live]
+AB[ea][eb][ac][bc][cc][dc][ec]AW[da][ab][bb][cb][db]
+(;W[ba]))`
+	p, err := LoadProblem(sgf)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Goal != Live || p.Game.ToPlay != gogame.White || p.Attacker != gogame.Black {
+		t.Fatalf("unexpected problem: goal %s, to play %s, attacker %s", p.Goal, p.Game.ToPlay, p.Attacker)
+	}
+	if p.Game.Rows != 5 || p.Game.Cols != 6 || p.Game.MoveNumber() != 0 {
+		t.Fatalf("expected an unplayed 6x5 position, got %dx%d after %d moves", p.Game.Cols, p.Game.Rows, p.Game.MoveNumber())
+	}
+	res, err := Solve(p)
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+	if res.Status != Alive || firstMove(t, p.Game, res) != "B5" {
+		t.Fatalf("expected White to live at B5, got %s %v", res.Status, res.PV)
+	}
+
+	if _, err := Solve(Problem{Game: p.Game, Attacker: gogame.Black, Target: coords(t, p.Game, "A5")}); err == nil {
+		t.Fatalf("expected an error for a target without a stone")
+	}
+}
+
+func TestPuzzleAttempt(t *testing.T) {
+	sgf := `(;SZ[6:5]C[Black to kill]
+AB[ea][eb][ac][bc][cc][dc][ec]AW[da][ab][bb][cb][db]
+(;B[ba];W[aa];B[ca]C[RIGHT])
+(;B[aa];W[ba]C[White lives])
+(;B[ca];W[ba]))`
+	p, err := LoadPuzzle("straight-three.sgf", sgf)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Problem.Goal != Kill || p.Problem.Attacker != gogame.Black {
+		t.Fatalf("unexpected problem: goal %s, attacker %s", p.Problem.Goal, p.Problem.Attacker)
+	}
+
+	a := p.Start()
+	reply, err := a.Play(coords(t, a.Game, "B5")[0])
+	if err != nil || reply == nil || gogame.FormatCoord(reply.Pos, 5, 6) != "A5" || a.Verdict != Pending {
+		t.Fatalf("expected White to answer A5, got %v (%v), verdict %s", reply, err, a.Verdict)
+	}
+	if _, err := a.Play(coords(t, a.Game, "A5")[0]); err == nil {
+		t.Fatalf("expected an occupied point to be rejected")
+	}
+	if _, err := a.Play(coords(t, a.Game, "C5")[0]); err != nil || a.Verdict != Correct || a.Comment != "RIGHT" {
+		t.Fatalf("expected the marked line to be correct, got %s %q (%v)", a.Verdict, a.Comment, err)
+	}
+
+	// Unmarked lines are wrong when the tree marks its correct ones.
+	a = p.Start()
+	if _, err := a.Play(coords(t, a.Game, "A5")[0]); err != nil || a.Verdict != Wrong || a.Comment != "White lives" {
+		t.Fatalf("expected A5 to fail, got %s %q (%v)", a.Verdict, a.Comment, err)
+	}
+	a = p.Start()
+	if _, err := a.Play(coords(t, a.Game, "F1")[0]); err != nil || a.Verdict != Wrong {
+		t.Fatalf("expected a move outside the tree to fail, got %s (%v)", a.Verdict, err)
+	}
+	if p.Problem.Game.MoveNumber() != 0 {
+		t.Fatalf("attempts must not change the puzzle position")
+	}
+}
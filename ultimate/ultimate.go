@@ -0,0 +1,228 @@
+package ultimate
+
+import (
+	"fmt"
+	"strings"
+
+	"boardgame/engine"
+	"boardgame/tictactoe"
+)
+
+// SubSize is the side length of each small board; the full grid is SubSize*SubSize.
+const SubSize = 3
+
+// Rules implements Ultimate Tic-Tac-Toe: nine 3x3 boards arranged in a 3x3 grid.
+// The cell chosen inside a small board sends the opponent to the matching small
+// board; if that board is already won or full the opponent may play anywhere.
+// Aligning three won small boards wins the game. The zero value is ready to
+// use.
+type Rules struct{}
+
+// NewRules returns Ultimate Tic-Tac-Toe rules.
+func NewRules() Rules {
+	return Rules{}
+}
+
+// small decides each small board and the grid of won boards.
+var small = tictactoe.Rules{Size: SubSize}
+
+func init() {
+	r := NewRules()
+	engine.Register(engine.GameDefinition{
+		Name:       "ultimate",
+		Title:      "Ultimate Tic-tac-toe",
+		MinPlayers: 2,
+		MaxPlayers: 2,
+		Rows:       SubSize * SubSize,
+		Cols:       SubSize * SubSize,
+		Players:    []engine.Player{{ID: 1, Name: "X", Token: "X"}, {ID: 2, Name: "O", Token: "O"}},
+		NewGame:    r.NewGame,
+		Rule:       r,
+		Render:     RenderBoard,
+		Notation:   engine.RowCol{Rule: r},
+		MoveHelp:   "row and column (1-9) from the top left, e.g. 5 5",
+	})
+}
+
+// NewGame constructs a game state on a 9x9 board.
+func (r Rules) NewGame(players []engine.Player) (*engine.Game, error) {
+	if len(players) != 2 {
+		return nil, fmt.Errorf("ultimate tic-tac-toe requires 2 players, got %d", len(players))
+	}
+	size := SubSize * SubSize
+	return engine.NewGame(engine.NewBoard(size, size), players)
+}
+
+// TargetBoard returns the index (0-8, row-major) of the small board the current
+// player must play in, or -1 when they may choose any undecided board.
+func (r Rules) TargetBoard(g *engine.Game) int {
+	if len(g.Log) == 0 {
+		return -1
+	}
+	last := g.Log[len(g.Log)-1].Pos
+	idx := (last.Row%SubSize)*SubSize + last.Col%SubSize
+	if r.decided(g.Board, idx) {
+		return -1
+	}
+	return idx
+}
+
+// ValidMoves returns empty cells in the small board the current player was sent to.
+func (r Rules) ValidMoves(g *engine.Game) []engine.Move {
+	if g.Outcome.Winner != nil || g.Outcome.Draw {
+		return nil
+	}
+	target := r.TargetBoard(g)
+	current := g.CurrentPlayer().ID
+	var moves []engine.Move
+	g.Board.ForEach(func(pos engine.Position, value int) {
+		if value != 0 {
+			return
+		}
+		idx := boardIndex(pos)
+		if target != -1 && idx != target {
+			return
+		}
+		if target == -1 && r.decided(g.Board, idx) {
+			return
+		}
+		moves = append(moves, engine.Move{PlayerID: current, Pos: pos})
+	})
+	return moves
+}
+
+// ApplyMove writes a player's mark if it lands in an allowed small board and
+// the big board is still undecided.
+func (r Rules) ApplyMove(g *engine.Game, m engine.Move) error {
+	if _, over := r.Status(g); over {
+		return fmt.Errorf("game is finished")
+	}
+	if m.PlayerID != g.CurrentPlayer().ID {
+		return fmt.Errorf("it is not player %d's turn", m.PlayerID)
+	}
+	if !g.Board.InBounds(m.Pos) {
+		return fmt.Errorf("position out of bounds: %+v", m.Pos)
+	}
+	idx := boardIndex(m.Pos)
+	if target := r.TargetBoard(g); target != -1 && idx != target {
+		return fmt.Errorf("move must be played in board %d", target+1)
+	}
+	if r.decided(g.Board, idx) {
+		return fmt.Errorf("board %d is already decided", idx+1)
+	}
+	if err := g.RecordMove(m); err != nil {
+		return err
+	}
+	if outcome, done := r.evaluate(g); done {
+		g.EndGame(outcome)
+	}
+	return nil
+}
+
+// Status reports whether the current position is terminal.
+func (r Rules) Status(g *engine.Game) (engine.Outcome, bool) {
+	if g.Outcome.Winner != nil || g.Outcome.Draw {
+		return g.Outcome, true
+	}
+	return r.evaluate(g)
+}
+
+// SubBoardWinners returns the winner of each small board (0 when undecided or drawn), row-major.
+func (r Rules) SubBoardWinners(b *engine.Board) [SubSize * SubSize]int {
+	var out [SubSize * SubSize]int
+	for i := range out {
+		out[i] = small.Winner(subBoard(b, i))
+	}
+	return out
+}
+
+// evaluate checks for an alignment of won small boards, or a draw once every board is decided.
+func (r Rules) evaluate(g *engine.Game) (engine.Outcome, bool) {
+	meta := engine.NewBoard(SubSize, SubSize)
+	for i, w := range r.SubBoardWinners(g.Board) {
+		if w != 0 {
+			_ = meta.SetAt(engine.Position{Row: i / SubSize, Col: i % SubSize}, w)
+		}
+	}
+	if winnerID := small.Winner(meta); winnerID != 0 {
+		return engine.Outcome{Winner: lookupPlayer(g.Players, winnerID)}, true
+	}
+	for i := 0; i < SubSize*SubSize; i++ {
+		if !r.decided(g.Board, i) {
+			return engine.Outcome{}, false
+		}
+	}
+	return engine.Outcome{Draw: true}, true
+}
+
+// decided reports whether a small board has a winner or no empty cells.
+func (r Rules) decided(b *engine.Board, idx int) bool {
+	sb := subBoard(b, idx)
+	return small.Winner(sb) != 0 || sb.IsFull()
+}
+
+// subBoard copies the small board at idx into its own 3x3 board.
+func subBoard(b *engine.Board, idx int) *engine.Board {
+	out := engine.NewBoard(SubSize, SubSize)
+	baseRow, baseCol := (idx/SubSize)*SubSize, (idx%SubSize)*SubSize
+	for r := 0; r < SubSize; r++ {
+		for c := 0; c < SubSize; c++ {
+			v, _ := b.Get(engine.Position{Row: baseRow + r, Col: baseCol + c})
+			_ = out.SetAt(engine.Position{Row: r, Col: c}, v)
+		}
+	}
+	return out
+}
+
+func boardIndex(pos engine.Position) int {
+	return (pos.Row/SubSize)*SubSize + pos.Col/SubSize
+}
+
+// RenderBoard returns the 9x9 grid with separators between small boards.
+func RenderBoard(g *engine.Game) string {
+	token := func(id int) string {
+		if id == 0 {
+			return "."
+		}
+		p := lookupPlayer(g.Players, id)
+		if p == nil || p.Token == "" {
+			return fmt.Sprintf("%d", id)
+		}
+		return p.Token
+	}
+
+	size := SubSize * SubSize
+	var sb strings.Builder
+	for r := 0; r < size; r++ {
+		if r > 0 && r%SubSize == 0 {
+			for i := 0; i < SubSize; i++ {
+				if i > 0 {
+					sb.WriteString("+")
+				}
+				sb.WriteString(strings.Repeat("-", SubSize*2+1))
+			}
+			sb.WriteString("\n")
+		}
+		for c := 0; c < size; c++ {
+			if c > 0 && c%SubSize == 0 {
+				sb.WriteString(" |")
+			}
+			val, _ := g.Board.Get(engine.Position{Row: r, Col: c})
+			sb.WriteString(" ")
+			sb.WriteString(token(val))
+		}
+		if r < size-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func lookupPlayer(players []engine.Player, id int) *engine.Player {
+	for i := range players {
+		if players[i].ID == id {
+			return &players[i]
+		}
+	}
+	return nil
+}
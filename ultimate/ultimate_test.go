@@ -0,0 +1,134 @@
+package ultimate
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"boardgame/engine"
+)
+
+var testPlayers = []engine.Player{
+	{ID: 1, Name: "X", Token: "X"},
+	{ID: 2, Name: "O", Token: "O"},
+}
+
+func TestMoveSendsOpponentToMatchingBoard(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if got := len(r.ValidMoves(g)); got != 81 {
+		t.Fatalf("expected 81 opening moves, got %d", got)
+	}
+	// Top-right cell of the centre board sends O to the top-right board.
+	apply(t, r, g, 1, 3, 5)
+	g.AdvanceTurn()
+	if got := r.TargetBoard(g); got != 2 {
+		t.Fatalf("expected target board 2, got %d", got)
+	}
+	for _, m := range r.ValidMoves(g) {
+		if boardIndex(m.Pos) != 2 {
+			t.Fatalf("move %+v outside target board", m.Pos)
+		}
+	}
+	if err := r.ApplyMove(g, engine.Move{PlayerID: 2, Pos: engine.Position{Row: 4, Col: 4}}); err == nil {
+		t.Fatalf("expected move outside target board to be rejected")
+	}
+}
+
+func TestDecidedBoardGivesFreeChoice(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	// X has already won the top-left board.
+	for col := 0; col < SubSize; col++ {
+		if err := g.Board.Set(engine.Position{Row: 0, Col: col}, 1); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	if w := r.SubBoardWinners(g.Board)[0]; w != 1 {
+		t.Fatalf("expected X to win board 0, got %d", w)
+	}
+	// O's top-left cell of the centre board sends X to the decided board 0.
+	g.AdvanceTurn()
+	apply(t, r, g, 2, 3, 3)
+	g.AdvanceTurn()
+	if got := r.TargetBoard(g); got != -1 {
+		t.Fatalf("expected free choice, got board %d", got)
+	}
+	for _, m := range r.ValidMoves(g) {
+		if boardIndex(m.Pos) == 0 {
+			t.Fatalf("decided board should not accept moves")
+		}
+	}
+}
+
+func TestDecidedGameRefusesMoves(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	// X has won the top row of small boards.
+	for idx := 0; idx < SubSize; idx++ {
+		for col := 0; col < SubSize; col++ {
+			if err := g.Board.Set(engine.Position{Row: 0, Col: idx*SubSize + col}, 1); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+		}
+	}
+	outcome, done := r.Status(g)
+	if !done || outcome.Winner == nil || outcome.Winner.ID != 1 {
+		t.Fatalf("expected X to have won, got %+v done=%v", outcome, done)
+	}
+	if err := r.ApplyMove(g, engine.Move{PlayerID: 1, Pos: engine.Position{Row: 4, Col: 4}}); err == nil {
+		t.Fatal("expected a move after the game was decided to be refused")
+	}
+	if v, _ := g.Board.Get(engine.Position{Row: 4, Col: 4}); v != 0 {
+		t.Fatalf("expected the refused move to leave the board unchanged, got %d", v)
+	}
+}
+
+func TestRandomGameFinishes(t *testing.T) {
+	r := NewRules()
+	g, err := r.NewGame(testPlayers)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	agents := map[int]engine.Agent{1: &engine.RandomAgent{Rand: rng}, 2: &engine.RandomAgent{Rand: rng}}
+	if _, err := engine.Play(g, r, agents); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+}
+
+func TestZeroRulesMatchNewRules(t *testing.T) {
+	var logs [2]string
+	for i, r := range []Rules{{}, NewRules()} {
+		g, err := r.NewGame(testPlayers)
+		if err != nil {
+			t.Fatalf("new game: %v", err)
+		}
+		rng := rand.New(rand.NewSource(7))
+		agents := map[int]engine.Agent{1: &engine.RandomAgent{Rand: rng}, 2: &engine.RandomAgent{Rand: rng}}
+		outcome, err := engine.Play(g, r, agents)
+		if err != nil {
+			t.Fatalf("play: %v", err)
+		}
+		logs[i] = fmt.Sprint(g.Log, outcome.Draw, outcome.Winner)
+	}
+	if logs[0] != logs[1] {
+		t.Fatalf("zero Rules played differently:\n%s\n%s", logs[0], logs[1])
+	}
+}
+
+func apply(t *testing.T, r Rules, g *engine.Game, id, row, col int) {
+	t.Helper()
+	if err := r.ApplyMove(g, engine.Move{PlayerID: id, Pos: engine.Position{Row: row, Col: col}}); err != nil {
+		t.Fatalf("apply %d,%d: %v", row, col, err)
+	}
+}
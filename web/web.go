@@ -0,0 +1,9 @@
+// Package web holds the browser client, embedded so the server binary is self-contained.
+package web
+
+import "embed"
+
+// FS contains index.html.
+//
+//go:embed index.html
+var FS embed.FS